@@ -0,0 +1,671 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const (
+	// fieldPathSeparator matches gql.FieldPathSeparator.
+	fieldPathSeparator = "_"
+	// resolverMethod is the method of gql.FieldResolver.
+	resolverMethod = "ResolveGraphQLField"
+)
+
+// graphqlName matches valid GraphQL names, it is the same as graphql.NameRegExp.
+var graphqlName = regexp.MustCompile("^[_a-zA-Z][_a-zA-Z0-9]*$")
+
+// config is the configuration for a single run of the generator.
+type config struct {
+	dir      string
+	types    []string
+	output   string
+	funcName string
+}
+
+// generate loads the package in the configured directory and returns the formatted source for the configured types.
+func generate(cfg config) ([]byte, error) {
+	pkg, err := loadPackage(cfg.dir, cfg.output)
+	if err != nil {
+		return nil, err
+	}
+
+	g := newGenerator(pkg, cfg.funcName)
+	for _, name := range cfg.types {
+		g.addRoot(strings.TrimSpace(name))
+	}
+	g.addResolverEmbedders()
+	if err := g.err(); err != nil {
+		return nil, err
+	}
+
+	src := g.source()
+	if err := g.err(); err != nil { // Errors with the FieldResolver implementations are found while writing them
+		return nil, err
+	}
+	formatted, err := format.Source(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format the generated code: %v\n%s", err, src)
+	}
+	return formatted, nil
+}
+
+// loadPackage loads the type checked package in the directory. The output file, if it exists, is replaced with an
+// empty file of the same package so code generated previously is ignored.
+func loadPackage(dir, output string) (*types.Package, error) {
+	overlay := make(map[string][]byte)
+	if existing, err := os.ReadFile(output); err == nil {
+		file, err := parser.ParseFile(token.NewFileSet(), output, existing, parser.PackageClauseOnly)
+		if err == nil {
+			abs, err := filepath.Abs(output)
+			if err != nil {
+				return nil, err
+			}
+			overlay[abs] = []byte("package " + file.Name.Name + "\n")
+		}
+	}
+
+	pkgs, err := packages.Load(&packages.Config{
+		Mode:    packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
+		Dir:     dir,
+		Overlay: overlay,
+	}, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the package in %q: %v", dir, err)
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected a single package in %q, found %d", dir, len(pkgs))
+	}
+	if len(pkgs[0].Errors) > 0 {
+		var msgs []string
+		for _, err := range pkgs[0].Errors {
+			msgs = append(msgs, err.Error())
+		}
+		return nil, fmt.Errorf("failed to load the package in %q:\n%s", dir, strings.Join(msgs, "\n"))
+	}
+	return pkgs[0].Types, nil
+}
+
+// generator walks the struct types of a package in the same way as gql.ObjectBuilder and writes the equivalent code.
+type generator struct {
+	pkg      *types.Package
+	funcName string
+	errs     []string
+
+	roots      []root
+	interfaces map[string]*fieldsFunc
+	funcs      typeutil.Map // struct type to *fieldsFunc
+	funcOrder  []*fieldsFunc
+	funcNames  map[string]bool
+	building   typeutil.Map // struct types whose fieldsFunc is being built, to find recursive types
+	resolvers  map[*types.Named]bool
+}
+
+// root is a struct named in the -type flag.
+type root struct {
+	name   string
+	embeds []string
+	fields *fieldsFunc
+}
+
+// fieldsFunc is a generated gql.StaticFields function for a struct type.
+type fieldsFunc struct {
+	name string
+	body bytes.Buffer
+}
+
+func newGenerator(pkg *types.Package, funcName string) *generator {
+	return &generator{
+		pkg:        pkg,
+		funcName:   funcName,
+		interfaces: make(map[string]*fieldsFunc),
+		funcNames:  make(map[string]bool),
+		resolvers:  make(map[*types.Named]bool),
+	}
+}
+
+// err returns an error with all the problems found so far, nil if there are none.
+func (g *generator) err() error {
+	if len(g.errs) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(g.errs, "\n"))
+}
+
+func (g *generator) errorf(format string, args ...interface{}) {
+	g.errs = append(g.errs, fmt.Sprintf(format, args...))
+}
+
+// addRoot adds the named struct as a GraphQL type along with interfaces for its root level embedded structs just as
+// ObjectBuilder.BuildInterfaces and BuildTypes do.
+func (g *generator) addRoot(name string) {
+	obj, ok := g.pkg.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		g.errorf("type %s not found in package %s", name, g.pkg.Path())
+		return
+	}
+	named, ok := types.Unalias(obj.Type()).(*types.Named)
+	if !ok || named.TypeParams().Len() > 0 {
+		g.errorf("type %s is not a struct", name)
+		return
+	}
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		g.errorf("type %s is not a struct", name)
+		return
+	}
+
+	r := root{name: named.Obj().Name()}
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		if !field.Anonymous() || !field.Exported() {
+			continue
+		}
+		embedType, ok := structType(field.Type())
+		if !ok {
+			continue
+		}
+		embedName := typeName(embedType)
+		fields := g.fieldsFuncFor(embedType, embedName)
+		if existing, ok := g.interfaces[embedName]; ok && existing != fields {
+			g.errorf("type %s embeds %s but a different struct named %s is embedded in another type", name, types.TypeString(embedType, nil), embedName)
+			continue
+		}
+		g.interfaces[embedName] = fields
+		r.embeds = append(r.embeds, embedName)
+	}
+	r.fields = g.fieldsFuncFor(named, r.name)
+	g.roots = append(g.roots, r)
+}
+
+// fieldsFuncFor returns the fieldsFunc for the struct type, generating it on first use. The hint is used to name the
+// function.
+func (g *generator) fieldsFuncFor(sType types.Type, hint string) *fieldsFunc {
+	if f, ok := g.funcs.At(sType).(*fieldsFunc); ok {
+		return f
+	}
+	if g.building.At(sType) != nil {
+		g.errorf("type %s is recursive, a struct can not contain itself as the GraphQL objects would be built endlessly", types.TypeString(sType, nil))
+		return &fieldsFunc{name: "nil"}
+	}
+	g.building.Set(sType, true)
+	defer g.building.Delete(sType)
+
+	if named, ok := sType.(*types.Named); ok {
+		g.addResolver(named)
+	}
+
+	f := &fieldsFunc{name: g.uniqueFuncName("graphql" + upperFirst(hint) + "Fields")}
+	g.writeFields(f, sType.Underlying().(*types.Struct), hint, g.sourceTypeName(sType))
+	g.funcs.Set(sType, f)
+	g.funcOrder = append(g.funcOrder, f)
+	return f
+}
+
+// sourceTypeName returns the name of the struct type if the resolvers of its fields can access them directly, empty if
+// they must use gql.ResolveByField. Only structs defined in the package without their own ResolveGraphQLField method
+// are accessed directly.
+func (g *generator) sourceTypeName(sType types.Type) string {
+	named, ok := sType.(*types.Named)
+	if !ok || named.Obj().Pkg() != g.pkg || named.TypeParams().Len() > 0 {
+		return ""
+	}
+	if obj, index, _ := types.LookupFieldOrMethod(named, true, g.pkg, resolverMethod); obj != nil && len(index) == 1 {
+		return ""
+	}
+	return named.Obj().Name()
+}
+
+// writeFields writes the body of the fieldsFunc for the struct following ObjectBuilder.buildFields. If the source type
+// name is set the fields, other than lists, are resolved directly from a source of that type or a pointer to it.
+func (g *generator) writeFields(f *fieldsFunc, st *types.Struct, hint, source string) {
+	w := &f.body
+	fmt.Fprintf(w, "fields := graphql.Fields{}\n")
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		if field.Anonymous() { // Fields from embedded structs are part of interfaces
+			continue
+		}
+		tag := reflect.StructTag(st.Tag(i))
+		name := fieldName(field.Name(), tag)
+
+		gtype, isList, ok := g.graphQLType(field.Type(), name, hint+upperFirst(field.Name()))
+		if !ok {
+			continue
+		}
+		if name == "" {
+			g.errorf("field %s.%s has no GraphQL name, the JSON name '-' can't be used for a GraphQL field", hint, field.Name())
+			continue
+		}
+		if !omitEmpty(tag) {
+			gtype = "graphql.NewNonNull(" + gtype + ")"
+		}
+
+		fmt.Fprintf(w, "fields[%q] = &graphql.Field{\n", name)
+		fmt.Fprintf(w, "Name: %q,\n", name)
+		fmt.Fprintf(w, "Type: %s,\n", gtype)
+		if source == "" || isList {
+			fmt.Fprintf(w, "Resolve: gql.ResolveByField(%q, parent),\n", name)
+		} else {
+			writeFieldResolve(w, source, name, field)
+		}
+		fmt.Fprintf(w, "ResolveSerial: true,\n")
+		if description := tag.Get("description"); strings.HasPrefix(description, "DEPRECATED:") {
+			fmt.Fprintf(w, "DeprecationReason: %q,\n", description)
+		} else if description != "" {
+			fmt.Fprintf(w, "Description: %q,\n", description)
+		}
+		fmt.Fprintf(w, "}\n")
+
+		if isList {
+			fmt.Fprintf(w, "ob.AddStaticListField(fields, %q, parent, %s, %t)\n", name, stringSlice(fieldPaths(listItemType(field.Type()))), tag.Get("aggregate") == "true")
+		}
+	}
+	fmt.Fprintf(w, "return fields\n")
+}
+
+// writeFieldResolve writes the resolve function for a field of the named struct type, it returns the field directly
+// for a source of the type or a pointer to it and otherwise falls back to gql.ResolveByField. The field is reported
+// to any QueryReporter and a nil pointer field resolves to null just as with gql.ResolveByField.
+func writeFieldResolve(w *bytes.Buffer, source, name string, field *types.Var) {
+	fmt.Fprintf(w, "Resolve: func(p graphql.ResolveParams) (interface{}, error) {\n")
+	fmt.Fprintf(w, "var s *%s\nswitch source := p.Source.(type) {\n", source)
+	fmt.Fprintf(w, "case %s:\ns = &source\ncase *%s:\ns = source\n}\n", source, source)
+	fmt.Fprintf(w, "if s == nil {\nreturn gql.ResolveByField(%q, parent)(p)\n}\n", name)
+	fmt.Fprintf(w, "if err := gql.ReportQueriedField(p, %q, parent); err != nil {\nreturn nil, err\n}\n", name)
+	if _, ok := field.Type().Underlying().(*types.Pointer); ok {
+		fmt.Fprintf(w, "if s.%s == nil {\nreturn nil, nil\n}\n", field.Name())
+	}
+	fmt.Fprintf(w, "return s.%s, nil\n},\n", field.Name())
+}
+
+// graphQLType returns the code for the GraphQL type of a struct field following ObjectBuilder.graphQLType, whether it
+// is a list and false if the type is not supported in which case the field is skipped.
+func (g *generator) graphQLType(t types.Type, name, hint string) (string, bool, bool) {
+	t = types.Unalias(t)
+	switch u := t.Underlying().(type) {
+	case *types.Pointer:
+		return g.graphQLType(u.Elem(), name, hint)
+	case *types.Struct:
+		if named, ok := t.(*types.Named); ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "time" {
+			return "graphql.DateTime", false, true
+		}
+		if named, ok := t.(*types.Named); ok {
+			hint = named.Obj().Name()
+		}
+		fields := g.fieldsFuncFor(t, hint)
+		return fmt.Sprintf("ob.BuildStaticObject(%q, parent, %s)", name, fields.name), false, true
+	case *types.Slice:
+		elem, _, ok := g.graphQLType(u.Elem(), name, hint)
+		if !ok {
+			g.errorf("field %s is a list of %s which has no GraphQL type", hint, types.TypeString(u.Elem(), nil))
+			return "", false, false
+		}
+		return "graphql.NewList(" + elem + ")", true, true
+	case *types.Map:
+		return "graphql.Map", false, true
+	case *types.Basic:
+		switch u.Kind() {
+		case types.Bool:
+			return "graphql.Boolean", false, true
+		case types.Float32, types.Float64:
+			return "graphql.Float", false, true
+		case types.Int, types.Int64:
+			return "graphql.Int", false, true
+		case types.String:
+			return "graphql.String", false, true
+		}
+	}
+	return "", false, false
+}
+
+// addResolver adds a FieldResolver implementation for the type if it is a struct defined in the package.
+func (g *generator) addResolver(named *types.Named) {
+	if named.Obj().Pkg() != g.pkg || named.TypeParams().Len() > 0 {
+		return
+	}
+	if _, ok := named.Underlying().(*types.Struct); ok {
+		g.resolvers[named] = true
+	}
+}
+
+// addResolverEmbedders adds a FieldResolver implementation for every struct in the package which would otherwise have
+// the ResolveGraphQLField method promoted from an embedded struct, that method would only find the fields of the
+// embedded struct.
+func (g *generator) addResolverEmbedders() {
+	for added := true; added; {
+		added = false
+		for _, name := range g.pkg.Scope().Names() {
+			obj, ok := g.pkg.Scope().Lookup(name).(*types.TypeName)
+			if !ok || obj.IsAlias() {
+				continue
+			}
+			named, ok := obj.Type().(*types.Named)
+			if !ok || g.resolvers[named] || named.TypeParams().Len() > 0 {
+				continue
+			}
+			if _, ok := named.Underlying().(*types.Struct); !ok {
+				continue
+			}
+			method, index, _ := types.LookupFieldOrMethod(named, true, g.pkg, resolverMethod)
+			if (method != nil && len(index) > 1) || g.embedsResolver(named, make(map[types.Type]bool)) {
+				g.resolvers[named] = true
+				added = true
+			}
+		}
+	}
+}
+
+// embedsResolver returns true if the struct embeds, at any depth, a struct with a generated FieldResolver.
+func (g *generator) embedsResolver(sType types.Type, visiting map[types.Type]bool) bool {
+	if visiting[sType] {
+		return false
+	}
+	visiting[sType] = true
+
+	st := sType.Underlying().(*types.Struct)
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		if !field.Anonymous() {
+			continue
+		}
+		embedType, ok := structType(field.Type())
+		if !ok {
+			continue
+		}
+		if named, ok := embedType.(*types.Named); ok && g.resolvers[named] {
+			return true
+		}
+		if g.embedsResolver(embedType, visiting) {
+			return true
+		}
+	}
+	return false
+}
+
+// source returns the unformatted generated source.
+func (g *generator) source() []byte {
+	var w bytes.Buffer
+	fmt.Fprintf(&w, "// Code generated by graphql-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&w, "package %s\n\n", g.pkg.Name())
+	fmt.Fprintf(&w, "import (\n\"github.com/GannettDigital/graphql\"\n\"github.com/GannettDigital/graphql-gen/gql\"\n)\n\n")
+
+	var rootNames []string
+	for _, r := range g.roots {
+		rootNames = append(rootNames, r.name)
+	}
+	fmt.Fprintf(&w, "// %s builds the GraphQL interfaces and types for the %s without reflection,\n", g.funcName, structList(rootNames))
+	fmt.Fprintf(&w, "// it is the equivalent of BuildInterfaces and BuildTypes for an ObjectBuilder of the structs.\n")
+	fmt.Fprintf(&w, "// The ObjectBuilder should be created with no structs and configured as usual.\n")
+	fmt.Fprintf(&w, "func %s(ob *gql.ObjectBuilder) (map[string]*graphql.Interface, []graphql.Type) {\n", g.funcName)
+	fmt.Fprintf(&w, "interfaces := make(map[string]*graphql.Interface)\n")
+	var ifaceNames []string
+	for name := range g.interfaces {
+		ifaceNames = append(ifaceNames, name)
+	}
+	sort.Strings(ifaceNames)
+	for _, name := range ifaceNames {
+		fmt.Fprintf(&w, "interfaces[%q] = ob.BuildStaticInterface(%q, %s)\n", name, name, g.interfaces[name].name)
+	}
+	fmt.Fprintf(&w, "\ntypes := []graphql.Type{\n")
+	for _, r := range g.roots {
+		fmt.Fprintf(&w, "ob.BuildStaticType(%q, %s, %s),\n", r.name, stringSlice(r.embeds), r.fields.name)
+	}
+	fmt.Fprintf(&w, "}\nreturn interfaces, types\n}\n")
+
+	for _, f := range g.funcOrder {
+		fmt.Fprintf(&w, "\nfunc %s(ob *gql.ObjectBuilder, parent string) graphql.Fields {\n%s}\n", f.name, f.body.Bytes())
+	}
+
+	var resolvers []*types.Named
+	for named := range g.resolvers {
+		resolvers = append(resolvers, named)
+	}
+	sort.Slice(resolvers, func(i, j int) bool { return resolvers[i].Obj().Name() < resolvers[j].Obj().Name() })
+	for _, named := range resolvers {
+		g.writeResolver(&w, named)
+	}
+	return w.Bytes()
+}
+
+// writeResolver writes the FieldResolver implementation for the struct, it finds fields the same way as
+// gql.ExtractField does with reflection. Nothing is written if the struct declares ResolveGraphQLField itself. The
+// method has a pointer receiver so the struct isn't copied for every field, a struct value is resolved with reflection.
+func (g *generator) writeResolver(w *bytes.Buffer, named *types.Named) {
+	if obj, index, _ := types.LookupFieldOrMethod(named, true, g.pkg, resolverMethod); obj != nil && len(index) == 1 {
+		if _, ok := obj.(*types.Var); ok {
+			g.errorf("type %s has a field named %s so it can't implement gql.FieldResolver", named.Obj().Name(), resolverMethod)
+		}
+		return
+	}
+
+	var accessors []accessor
+	collectAccessors(named, nil, make(map[string]bool), make(map[types.Type]bool), &accessors)
+
+	fmt.Fprintf(w, "\n// %s implements gql.FieldResolver so the fields of %s are found without reflection.\n", resolverMethod, named.Obj().Name())
+	fmt.Fprintf(w, "func (s *%s) %s(name string) (interface{}, bool) {\nswitch name {\n", named.Obj().Name(), resolverMethod)
+	for _, a := range accessors {
+		field := a.path[len(a.path)-1]
+		if !field.Exported() { // ExtractField finds unexported fields but can't return their value
+			continue
+		}
+		fmt.Fprintf(w, "case %q:\n", a.name)
+		selector := "s"
+		for i, step := range a.path {
+			if !step.Exported() && step.Pkg() != g.pkg {
+				g.errorf("type %s has field %q within %s which can't be accessed from package %s", named.Obj().Name(), a.name, step.Name(), g.pkg.Name())
+				return
+			}
+			selector += "." + step.Name()
+			if _, ok := step.Type().Underlying().(*types.Pointer); ok && i < len(a.path)-1 {
+				fmt.Fprintf(w, "if %s == nil {\nreturn nil, true\n}\n", selector)
+			}
+		}
+		fmt.Fprintf(w, "return %s, true\n", selector)
+	}
+	fmt.Fprintf(w, "}\nreturn nil, false\n}\n")
+}
+
+// accessor is a field found by name in a struct, the path includes any embedded structs the field is within.
+type accessor struct {
+	name string
+	path []*types.Var
+}
+
+// collectAccessors adds the fields of the struct type to accessors just as gql does in addStructFields, fields of the
+// struct itself take precedence over those of embedded structs which are searched in order.
+func collectAccessors(sType types.Type, path []*types.Var, seen map[string]bool, visiting map[types.Type]bool, accessors *[]accessor) {
+	if visiting[sType] {
+		return
+	}
+	visiting[sType] = true
+	defer delete(visiting, sType)
+
+	st := sType.Underlying().(*types.Struct)
+	var embedded []*types.Var
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		if _, ok := structType(field.Type()); ok && field.Anonymous() {
+			embedded = append(embedded, field)
+		}
+		name := fieldName(field.Name(), reflect.StructTag(st.Tag(i)))
+		if seen[name] || name == "" {
+			continue
+		}
+		seen[name] = true
+		*accessors = append(*accessors, accessor{name: name, path: append(append([]*types.Var{}, path...), field)})
+	}
+
+	for _, field := range embedded {
+		embedType, _ := structType(field.Type())
+		collectAccessors(embedType, append(append([]*types.Var{}, path...), field), seen, visiting, accessors)
+	}
+}
+
+// uniqueFuncName returns the name or, if already used, the name with a number added.
+func (g *generator) uniqueFuncName(name string) string {
+	unique := name
+	for i := 2; g.funcNames[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	g.funcNames[unique] = true
+	return unique
+}
+
+// fieldName returns the GraphQL name of a struct field just as gql's fieldName.
+func fieldName(name string, tag reflect.StructTag) string {
+	splits := strings.Split(tag.Get("json"), ",")
+	if len(splits) > 0 {
+		jsonName := splits[0]
+		if jsonName == "-" && len(splits) == 1 {
+			return ""
+		}
+		jsonName = strings.Replace(jsonName, "_", "", -1)
+		if jsonName != "" && graphqlName.MatchString(jsonName) {
+			return jsonName
+		}
+	}
+	return strings.ToLower(strings.Replace(name, "_", "", -1))
+}
+
+// omitEmpty returns true if the JSON struct tag includes omitempty, such fields are nullable.
+func omitEmpty(tag reflect.StructTag) bool {
+	for _, split := range strings.Split(tag.Get("json"), ",") {
+		if split == "omitempty" {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldPaths returns the sorted paths of the fields within the type just as gql's fieldPaths.
+func fieldPaths(t types.Type) []string {
+	found := make(map[string]bool)
+	walkFieldPaths(t, "", make(map[types.Type]bool), found)
+
+	paths := make([]string, 0, len(found))
+	for path := range found {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// walkFieldPaths adds the path of each field in the struct type to found just as gql's walkFieldPaths.
+func walkFieldPaths(t types.Type, prefix string, visiting map[types.Type]bool, found map[string]bool) {
+	sType, ok := structType(t)
+	if !ok || isTimePackage(sType) || visiting[sType] {
+		return
+	}
+	visiting[sType] = true
+	defer delete(visiting, sType)
+
+	st := sType.Underlying().(*types.Struct)
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		if !field.Exported() && !field.Anonymous() {
+			continue
+		}
+		if field.Anonymous() {
+			walkFieldPaths(field.Type(), prefix, visiting, found)
+			continue
+		}
+
+		name := fieldName(field.Name(), reflect.StructTag(st.Tag(i)))
+		switch name {
+		case "", "true", "false", "null":
+			continue
+		}
+		path := name
+		if prefix != "" {
+			path = prefix + fieldPathSeparator + name
+		}
+		found[path] = true
+		walkFieldPaths(field.Type(), path, visiting, found)
+	}
+}
+
+// structType returns the type after following any pointers and whether it is a struct.
+func structType(t types.Type) (types.Type, bool) {
+	t = types.Unalias(t)
+	for {
+		ptr, ok := t.Underlying().(*types.Pointer)
+		if !ok {
+			break
+		}
+		t = types.Unalias(ptr.Elem())
+	}
+	_, ok := t.Underlying().(*types.Struct)
+	return t, ok
+}
+
+// listItemType returns the type of the items in a list type, pointers to the list are followed.
+func listItemType(t types.Type) types.Type {
+	for {
+		ptr, ok := t.Underlying().(*types.Pointer)
+		if !ok {
+			break
+		}
+		t = ptr.Elem()
+	}
+	if slice, ok := t.Underlying().(*types.Slice); ok {
+		return slice.Elem()
+	}
+	return t
+}
+
+// isTimePackage returns true if the type is defined in the time package.
+func isTimePackage(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "time"
+}
+
+// typeName returns the name of a named type, empty for other types.
+func typeName(t types.Type) string {
+	if named, ok := t.(*types.Named); ok {
+		return named.Obj().Name()
+	}
+	return ""
+}
+
+// upperFirst returns the string with the first letter upper case.
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// stringSlice returns the code for a []string literal of the values, nil if there are none.
+func stringSlice(values []string) string {
+	if len(values) == 0 {
+		return "nil"
+	}
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("%q", value)
+	}
+	return "[]string{" + strings.Join(quoted, ", ") + "}"
+}
+
+// structList returns a description of the struct names for a doc comment, ie 'Article and Video structs'.
+func structList(names []string) string {
+	if len(names) == 1 {
+		return names[0] + " struct"
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1] + " structs"
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// gentestDir is the package in the gql module with code generated by graphql-gen, it is tested there.
+const gentestDir = "../../gql/internal/gentest"
+
+func TestGenerateUpToDate(t *testing.T) {
+	output := filepath.Join(gentestDir, "graphql_gen.go")
+	want, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := generate(config{dir: gentestDir, types: []string{"Article", "Video"}, output: output, funcName: "BuildGraphQLTypes"})
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s is out of date, run go generate in %s", output, gentestDir)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tests := []struct {
+		description string
+		types       []string
+		wantErr     string
+	}{
+		{
+			description: "Unknown type",
+			types:       []string{"Missing"},
+			wantErr:     "type Missing not found",
+		},
+		{
+			description: "Not a struct",
+			types:       []string{"NotAStruct"},
+			wantErr:     "type NotAStruct is not a struct",
+		},
+		{
+			description: "Recursive type",
+			types:       []string{"Recursive"},
+			wantErr:     "is recursive",
+		},
+		{
+			description: "Field with no name",
+			types:       []string{"Ignored"},
+			wantErr:     "field Ignored.Hidden has no GraphQL name",
+		},
+		{
+			description: "List of an unsupported type",
+			types:       []string{"UnsupportedList"},
+			wantErr:     "field UnsupportedListValues is a list of interface{}",
+		},
+		{
+			description: "Field named as the FieldResolver method",
+			types:       []string{"ResolverField"},
+			wantErr:     "type ResolverField has a field named ResolveGraphQLField",
+		},
+	}
+
+	for _, test := range tests {
+		_, err := generate(config{dir: "testdata/errors", types: test.types, output: "testdata/errors/graphql_gen.go", funcName: "BuildGraphQLTypes"})
+		if err == nil {
+			t.Errorf("Test %q - got no error, want %q", test.description, test.wantErr)
+		} else if !strings.Contains(err.Error(), test.wantErr) {
+			t.Errorf("Test %q - got error %q, want %q", test.description, err, test.wantErr)
+		}
+	}
+}
+
+func TestFieldName(t *testing.T) {
+	tests := []struct {
+		description string
+		name        string
+		tag         string
+		want        string
+	}{
+		{description: "No tag", name: "Some_Field", want: "somefield"},
+		{description: "JSON name", name: "Field", tag: `json:"field_name,omitempty"`, want: "fieldname"},
+		{description: "Invalid JSON name", name: "Field", tag: `json:"field-name"`, want: "field"},
+		{description: "Options only", name: "Field", tag: `json:",omitempty"`, want: "field"},
+		{description: "Ignored", name: "Field", tag: `json:"-"`, want: ""},
+		{description: "Named dash", name: "Field", tag: `json:"-,"`, want: "field"},
+	}
+
+	for _, test := range tests {
+		if got := fieldName(test.name, reflect.StructTag(test.tag)); got != test.want {
+			t.Errorf("Test %q - got %q, want %q", test.description, got, test.want)
+		}
+	}
+}
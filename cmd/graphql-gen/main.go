@@ -0,0 +1,76 @@
+// Command graphql-gen generates Go code which builds the GraphQL interfaces and types for a set of structs just as
+// the gql.ObjectBuilder does but without reflection, along with a gql.FieldResolver implementation for each struct so
+// the fields are resolved, filtered and sorted with direct field access. The field resolvers access the fields of a
+// struct or a pointer to it directly, ResolveGraphQLField has a pointer receiver so lists of struct values are
+// filtered and sorted with reflection.
+//
+// Usage:
+//
+//	graphql-gen -type Article,Video [-output graphql_gen.go] [-func BuildGraphQLTypes] [directory]
+//
+// The directory, by default the current one, holds the package with the structs named by -type and the generated
+// file is written to it. This makes it suited to go:generate, ie
+//
+//	//go:generate go run github.com/GannettDigital/graphql-gen/cmd/graphql-gen -type Article,Video
+//
+// The generated function takes an ObjectBuilder created with no structs and returns the interfaces and types which
+// BuildInterfaces and BuildTypes would for the structs, the options of the ObjectBuilder such as the name prefix,
+// connection mode and field additions are used as usual:
+//
+//	ob, err := gql.NewObjectBuilder(nil, "", nil)
+//	...
+//	ob.SetConnectionMode(gql.ListAndConnectionFields)
+//	interfaces, types := content.BuildGraphQLTypes(ob)
+//
+// Problems which the ObjectBuilder would only find when the schema is built or the structs are used, such as a list
+// of an unsupported type or a recursive struct, are reported by graphql-gen instead.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("graphql-gen: ")
+
+	typeNames := flag.String("type", "", "comma-separated list of struct type names; required")
+	output := flag.String("output", "graphql_gen.go", "output file name, relative to the package directory")
+	funcName := flag.String("func", "BuildGraphQLTypes", "name of the generated function which builds the types")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: graphql-gen -type T[,T...] [flags] [directory]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *typeNames == "" || flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	dir := "."
+	if flag.NArg() == 1 {
+		dir = flag.Arg(0)
+	}
+	outputPath := *output
+	if !filepath.IsAbs(outputPath) {
+		outputPath = filepath.Join(dir, outputPath)
+	}
+
+	src, err := generate(config{
+		dir:      dir,
+		types:    strings.Split(*typeNames, ","),
+		output:   outputPath,
+		funcName: *funcName,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(outputPath, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
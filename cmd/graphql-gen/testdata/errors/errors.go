@@ -0,0 +1,27 @@
+// Package errors holds structs which graphql-gen can't generate code for.
+package errors
+
+// Recursive contains itself.
+type Recursive struct {
+	Name  string
+	Child *Recursive
+}
+
+// Ignored has a field with no GraphQL name.
+type Ignored struct {
+	Name   string
+	Hidden string `json:"-"`
+}
+
+// UnsupportedList has a list with items which have no GraphQL type.
+type UnsupportedList struct {
+	Values []interface{}
+}
+
+// NotAStruct is not a struct.
+type NotAStruct string
+
+// ResolverField has a field which conflicts with the FieldResolver method.
+type ResolverField struct {
+	ResolveGraphQLField string
+}
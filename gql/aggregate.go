@@ -0,0 +1,264 @@
+package gql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/GannettDigital/graphql"
+	"github.com/GannettDigital/graphql/language/ast"
+)
+
+const (
+	aggregateSuffix       = "Aggregate"
+	aggregateFieldArgName = "field"
+	aggregateTag          = "aggregate"
+)
+
+var graphqlAggregateValue = graphql.NewScalar(graphql.ScalarConfig{
+	Name:         "AggregateValue",
+	Description:  "A value computed by a list aggregate, either a string, number, boolean or RFC 3339 time.",
+	Serialize:    serializeAggregateValue,
+	ParseValue:   func(value interface{}) interface{} { return nil },
+	ParseLiteral: func(valueAST ast.Value) interface{} { return nil },
+})
+
+// listAggregate is the value resolved for an aggregate field, count is the number of list items matching the filter
+// and values are the non-null values of the aggregated field in those items.
+type listAggregate struct {
+	count  int
+	values []interface{}
+}
+
+// SetAggregateFields sets whether an aggregate field named '<name>Aggregate' is built next to every list field, by
+// default it is not. An aggregate field can also be enabled for a single list by adding the struct tag
+// `aggregate:"true"` to the list field. This must be called before BuildInterfaces or BuildTypes.
+func (ob *ObjectBuilder) SetAggregateFields(enabled bool) {
+	ob.aggregates = enabled
+}
+
+// aggregateObject returns the object for aggregate fields shared by all lists, it is created on first use.
+// The object has the fields 'count', 'sum', 'min', 'max', 'avg' and 'distinctValues'.
+func (ob *ObjectBuilder) aggregateObject() *graphql.Object {
+	if ob.aggregate != nil {
+		return ob.aggregate
+	}
+
+	ob.aggregate = graphql.NewObject(graphql.ObjectConfig{
+		Name: ob.prefix + "ListAggregate",
+		Fields: graphql.Fields{
+			"count": &graphql.Field{
+				Name:        "count",
+				Type:        graphql.NewNonNull(graphql.Int),
+				Description: "The number of list items matching the filter.",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*listAggregate).count, nil
+				},
+			},
+			"sum": &graphql.Field{
+				Name:        "sum",
+				Type:        graphql.Float,
+				Description: "The sum of the numeric field values, null if there are none.",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					sum, n, err := p.Source.(*listAggregate).sum()
+					if err != nil || n == 0 {
+						return nil, err
+					}
+					return sum, nil
+				},
+			},
+			"avg": &graphql.Field{
+				Name:        "avg",
+				Type:        graphql.Float,
+				Description: "The mean of the numeric field values, null if there are none.",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					sum, n, err := p.Source.(*listAggregate).sum()
+					if err != nil || n == 0 {
+						return nil, err
+					}
+					return sum / float64(n), nil
+				},
+			},
+			"min": &graphql.Field{
+				Name:        "min",
+				Type:        graphqlAggregateValue,
+				Description: "The smallest of the field values, null if there are none.",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*listAggregate).extreme(-1)
+				},
+			},
+			"max": &graphql.Field{
+				Name:        "max",
+				Type:        graphqlAggregateValue,
+				Description: "The largest of the field values, null if there are none.",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*listAggregate).extreme(1)
+				},
+			},
+			"distinctValues": &graphql.Field{
+				Name:        "distinctValues",
+				Type:        graphql.NewList(graphqlAggregateValue),
+				Description: "The distinct field values in the order they are first found.",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return distinctTransform{}.Transform(p.Source.(*listAggregate).values)
+				},
+			},
+		},
+	})
+	return ob.aggregate
+}
+
+// ResolveListAggregate accepts an aggregate field name, a name of the list field, and a parent name. It returns the
+// aggregate of the list items matching the optional filter argument, the filter is the same as that of
+// ResolveListField. The values aggregated are those of the field named in the 'field' argument, which may include
+// FieldPathSeparator, or the items themselves if no field is given. It will also report the queried field to the
+// QueryReporter and the filter to the QueryFunctionReporter if found in the context.
+func ResolveListAggregate(aggregateFieldName, listFieldName, parent string) graphql.FieldResolveFn {
+	return collectListErrors(func(p graphql.ResolveParams) (interface{}, error) {
+		if qr, ok := p.Context.Value(QueryReporterContextKey).(QueryReporter); ok && qr != nil {
+			if err := qr.QueriedField(fullFieldName(aggregateFieldName, parent)); err != nil {
+				return nil, err
+			}
+		}
+
+		args, err := parseListArguments(p, aggregateFieldName, parent)
+		if err != nil {
+			return nil, err
+		}
+
+		items, err := filteredListItems(p, args, listFieldName, parent)
+		if err != nil {
+			return nil, err
+		}
+
+		field, _ := p.Args[aggregateFieldArgName].(string)
+		agg := &listAggregate{count: len(items)}
+		path := compileFieldPath(field)
+		for _, item := range items {
+			raw := item
+			if field != "" {
+				raw, _, err = path.lookup(item)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if value, ok := aggregateValue(raw); ok {
+				agg.values = append(agg.values, value)
+			}
+		}
+
+		return agg, nil
+	})
+}
+
+// aggregateValue converts a field value to that used in aggregates, times become a time.Time in UTC and other values
+// the canonical values returned by coerceValue. Values which can't be converted are returned as is.
+// False is returned if the value is null.
+func aggregateValue(raw interface{}) (interface{}, bool) {
+	if isNil(raw) {
+		return nil, false
+	}
+	switch t := raw.(type) {
+	case time.Time:
+		return t.UTC(), true
+	case *time.Time:
+		return t.UTC(), true
+	}
+	if value, ok := coerceValue(raw); ok {
+		return value, true
+	}
+	return raw, true
+}
+
+// sum returns the sum of the values and the number of values summed. An error is returned if any value is not a
+// number.
+func (agg *listAggregate) sum() (float64, int, error) {
+	var sum float64
+	for _, value := range agg.values {
+		switch v := value.(type) {
+		case int64:
+			sum += float64(v)
+		case uint64:
+			sum += float64(v)
+		case float64:
+			sum += v
+		default:
+			return 0, 0, fmt.Errorf("unable to sum a value of type %T, only numbers can be summed", value)
+		}
+	}
+	return sum, len(agg.values), nil
+}
+
+// extreme returns the smallest value when direction is negative or the largest when positive. An error is returned
+// if the values can't be ordered, they must all be numbers, all strings or all times.
+func (agg *listAggregate) extreme(direction int) (interface{}, error) {
+	var result interface{}
+	for i, value := range agg.values {
+		if i == 0 {
+			if _, err := compareAggregateValues(value, value); err != nil {
+				return nil, err
+			}
+			result = value
+			continue
+		}
+		c, err := compareAggregateValues(value, result)
+		if err != nil {
+			return nil, err
+		}
+		if c*direction > 0 {
+			result = value
+		}
+	}
+	return result, nil
+}
+
+// compareAggregateValues compares two values as returned by aggregateValue, the result is negative if a is less than
+// b, positive if a is greater than b and 0 if they are equal.
+func compareAggregateValues(a, b interface{}) (int, error) {
+	switch a := a.(type) {
+	case string:
+		if b, ok := b.(string); ok {
+			switch {
+			case a < b:
+				return -1, nil
+			case a > b:
+				return 1, nil
+			}
+			return 0, nil
+		}
+	case time.Time:
+		if b, ok := b.(time.Time); ok {
+			switch {
+			case a.Before(b):
+				return -1, nil
+			case a.After(b):
+				return 1, nil
+			}
+			return 0, nil
+		}
+	case int64, uint64, float64:
+		if c, ok := compareNumbers(a, b); ok {
+			return c, nil
+		}
+		if valueCategory(b) == numberCategory {
+			return 0, nil // NaN is neither smaller nor larger
+		}
+	}
+	return 0, fmt.Errorf("unable to order values of type %T and %T, all values must be numbers, strings or times", a, b)
+}
+
+// serializeAggregateValue is the graphql.SerializeFn for the AggregateValue scalar.
+func serializeAggregateValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return v.Format(time.RFC3339Nano)
+	}
+	if c, ok := coerceValue(value); ok {
+		return c
+	}
+	return nil
+}
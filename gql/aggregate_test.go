@@ -0,0 +1,179 @@
+package gql
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/GannettDigital/graphql"
+)
+
+func TestResolveListAggregate(t *testing.T) {
+	type item struct {
+		Name      string
+		Value     int
+		Score     *float64
+		Published time.Time
+	}
+	type testStruct struct {
+		Items  []item
+		Tagged []string `aggregate:"true"`
+	}
+	half := 0.5
+	testData := testStruct{
+		Items: []item{
+			{Name: "c", Value: 3, Published: time.Date(2019, 10, 3, 0, 0, 0, 0, time.UTC)},
+			{Name: "a", Value: 1, Score: &half, Published: time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)},
+			{Name: "b", Value: 3, Published: time.Date(2019, 10, 2, 0, 0, 0, 0, time.UTC)},
+		},
+		Tagged: []string{"x", "y", "x"},
+	}
+
+	tests := []struct {
+		description string
+		enabled     bool
+		query       string
+		want        string
+		wantErr     bool
+	}{
+		{
+			description: "Numeric aggregates",
+			enabled:     true,
+			query:       `query { q { itemsAggregate(field: "value") { count sum avg min max distinctValues } } }`,
+			want:        `{"data":{"q":{"itemsAggregate":{"avg":2.3333333333333335,"count":3,"distinctValues":[3,1],"max":3,"min":1,"sum":7}}}}`,
+		},
+		{
+			description: "Aggregates with a filter",
+			enabled:     true,
+			query:       `query { q { itemsAggregate(field: "value", filter: {Field: "name", Operation: "!=", Argument: {Value: "a"}}) { count sum } } }`,
+			want:        `{"data":{"q":{"itemsAggregate":{"count":2,"sum":6}}}}`,
+		},
+		{
+			description: "Aggregates skip null values",
+			enabled:     true,
+			query:       `query { q { itemsAggregate(field: "score") { count sum avg } } }`,
+			want:        `{"data":{"q":{"itemsAggregate":{"avg":0.5,"count":3,"sum":0.5}}}}`,
+		},
+		{
+			description: "String and time min and max",
+			enabled:     true,
+			query:       `query { q { a: itemsAggregate(field: "name") { min max } b: itemsAggregate(field: "published") { min max } } }`,
+			want:        `{"data":{"q":{"a":{"max":"c","min":"a"},"b":{"max":"2019-10-03T00:00:00Z","min":"2019-10-01T00:00:00Z"}}}}`,
+		},
+		{
+			description: "Sum of strings",
+			enabled:     true,
+			query:       `query { q { itemsAggregate(field: "name") { sum } } }`,
+			wantErr:     true,
+		},
+		{
+			description: "Field not found",
+			enabled:     true,
+			query:       `query { q { itemsAggregate(field: "bogus") { count } } }`,
+			wantErr:     true,
+		},
+		{
+			description: "Enabled by struct tag",
+			query:       `query { q { taggedAggregate { count distinctValues } } }`,
+			want:        `{"data":{"q":{"taggedAggregate":{"count":3,"distinctValues":["x","y"]}}}}`,
+		},
+		{
+			description: "Not enabled",
+			query:       `query { q { itemsAggregate { count } } }`,
+			wantErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		ob, err := NewObjectBuilder([]interface{}{testStruct{}}, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ob.SetAggregateFields(test.enabled)
+		types := ob.BuildTypes()
+		s, err := graphql.NewSchema(graphql.SchemaConfig{
+			Query: graphql.NewObject(graphql.ObjectConfig{
+				Name: "Query",
+				Fields: graphql.Fields{
+					"q": &graphql.Field{
+						Type: types[0],
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							return testData, nil
+						},
+					},
+				},
+			}),
+			Types: types,
+		})
+		if err != nil {
+			t.Fatalf("Test %q - failed to build schema: %v", test.description, err)
+		}
+
+		resp := graphql.Do(graphql.Params{Context: context.Background(), Schema: s, RequestString: test.query})
+		if gotErr := len(resp.Errors) != 0; gotErr != test.wantErr {
+			t.Errorf("Test %q - got errors %v, want error %t", test.description, resp.Errors, test.wantErr)
+		}
+		if test.wantErr {
+			continue
+		}
+
+		gotBytes, err := json.Marshal(resp)
+		if err != nil {
+			t.Errorf("Test %q - failed to Marshal: %v", test.description, err)
+		}
+		if got, want := string(gotBytes), test.want; got != want {
+			t.Errorf("Test %q - got %v, want %v", test.description, got, want)
+		}
+	}
+}
+
+func TestListAggregateExtreme(t *testing.T) {
+	tests := []struct {
+		description string
+		values      []interface{}
+		wantMin     interface{}
+		wantMax     interface{}
+		wantErr     bool
+	}{
+		{
+			description: "No values",
+		},
+		{
+			description: "Mixed numbers",
+			values:      []interface{}{int64(2), 1.5, uint64(math.MaxUint64)},
+			wantMin:     1.5,
+			wantMax:     uint64(math.MaxUint64),
+		},
+		{
+			description: "Numbers and strings",
+			values:      []interface{}{int64(2), "a"},
+			wantErr:     true,
+		},
+		{
+			description: "Booleans",
+			values:      []interface{}{true},
+			wantErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		agg := &listAggregate{values: test.values}
+		gotMin, err := agg.extreme(-1)
+		if (err != nil) != test.wantErr {
+			t.Errorf("Test %q - got err %v, want err %t", test.description, err, test.wantErr)
+		}
+		gotMax, _ := agg.extreme(1)
+		if test.wantErr {
+			continue
+		}
+		if !reflect.DeepEqual(gotMin, test.wantMin) {
+			t.Errorf("Test %q - got min %v, want %v", test.description, gotMin, test.wantMin)
+		}
+		if !reflect.DeepEqual(gotMax, test.wantMax) {
+			t.Errorf("Test %q - got max %v, want %v", test.description, gotMax, test.wantMax)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/GannettDigital/graphql"
 )
@@ -39,12 +40,22 @@ var graphqlKinds = map[reflect.Kind]graphql.Type{
 	reflect.Map:     graphql.Map,
 }
 
+// ListFunctions describes the list functions used for a list field, it is reported to the QueryFunctionReporter.
+// SortField and SortOrder are those of the first sort key, SortKeys includes every sort key in order.
 type ListFunctions struct {
 	SortField string
 	SortOrder string
+	SortKeys  []ListSortKey
 	Filter    string
 }
 
+// ListSortKey describes a single sort key used in sorting a list.
+type ListSortKey struct {
+	Field string
+	Order string
+	Nulls string
+}
+
 // QueryReporter defines the interface used to report details on the GraphQL queries being performed.
 // Implementations must be concurrency safe and added to the request context using QueryReporterContextKey as the
 // context value key.
@@ -80,6 +91,15 @@ type QueryFunctionReporter interface {
 // simply to pull the correct field from that object. The default resolve function also looks for a QueryReporter in
 // the context and if it exists reports the QueriedFields. If the field is a List the default function is
 // ResolveListField which works the same way but adds a filter parameter optionally used to filter the list items.
+// Each list field has a 'total<Name>' field, resolved with ResolveTotalCount, and a '<name>EndCursor' field, resolved
+// with ResolveListEndCursor, giving the cursor to request the page after the one returned by the list field.
+// With SetConnectionMode list fields can also be built as Relay style connections resolved with ResolveListConnection.
+// With SetFilteredCountFields a 'filteredTotal<Name>' field, resolved with ResolveFilteredCount, is added for list
+// fields to count the items matching a filter. With SetGroupFields a '<name>Groups' field, resolved with
+// ResolveListGroups, is added for list fields to group the list items by a field.
+// With SetAggregateFields or the `aggregate:"true"` struct tag an aggregate field is added for list fields which is
+// resolved with ResolveListAggregate. With SetTypedListArguments the filter and sort arguments are built as input
+// objects so they are checked when the query is validated.
 //
 // It is also possible to specify custom fields which can be setup with custom resolve functions. See fieldAdditions on
 // the NewObjectBulider function and the AddCustomFields method.
@@ -95,6 +115,17 @@ type ObjectBuilder struct {
 	objects         map[string]*graphql.Object
 	prefix          string
 	structs         []interface{}
+	connectionMode  ConnectionMode
+	pageInfo        *graphql.Object
+	filteredCounts  bool
+	groups          bool
+	aggregates      bool
+	aggregate       *graphql.Object
+	typedArguments  bool
+	listOperation   *graphql.Enum
+	sortOrder       *graphql.Enum
+	sortNulls       *graphql.Enum
+	filterArgument  *graphql.Scalar
 }
 
 // NewObjectBuilder creates an ObjectBuilder for the given structs and fieldAdditions.
@@ -161,20 +192,36 @@ func (ob *ObjectBuilder) BuildInterfaces() map[string]*graphql.Interface {
 	}
 
 	for name, embed := range allEmbeds {
-		sType := reflect.TypeOf(embed)
-		iName := ob.prefix + name
-		ob.interfaceFields[name] = ob.buildFields(sType, iName, nil)
-
-		ob.interfaces[name] = graphql.NewInterface(graphql.InterfaceConfig{
-			Name:        iName,
-			Fields:      ob.interfaceFields[name],
-			ResolveType: ob.resolveObjectByName,
-		})
+		ob.buildInterface(name, ob.structFields(reflect.TypeOf(embed)))
 	}
 
 	return ob.interfaces
 }
 
+// fieldsFunc builds the fields of an object or interface, see buildFields.
+type fieldsFunc func(parent string, baseFields graphql.Fields) graphql.Fields
+
+// structFields returns the fieldsFunc which builds the fields of the struct type with buildFields.
+func (ob *ObjectBuilder) structFields(sType reflect.Type) fieldsFunc {
+	return func(parent string, baseFields graphql.Fields) graphql.Fields {
+		return ob.buildFields(sType, parent, baseFields)
+	}
+}
+
+// buildInterface creates the GraphQL interface for the embedded struct with the given name and adds it along with its
+// fields to those of the ObjectBuilder.
+func (ob *ObjectBuilder) buildInterface(name string, fields fieldsFunc) *graphql.Interface {
+	iName := ob.prefix + name
+	ob.interfaceFields[name] = fields(iName, nil)
+
+	ob.interfaces[name] = graphql.NewInterface(graphql.InterfaceConfig{
+		Name:        iName,
+		Fields:      ob.interfaceFields[name],
+		ResolveType: ob.resolveObjectByName,
+	})
+	return ob.interfaces[name]
+}
+
 // BuildTypes creates the GraphQL types from the sources structs. The output of this method is suitable for directly
 // including in graphql.SchemaConfig which when coupled with a graphql.Query can be built into the a GraphQL schema.
 func (ob *ObjectBuilder) BuildTypes() []graphql.Type {
@@ -194,12 +241,22 @@ func (ob *ObjectBuilder) BuildTypes() []graphql.Type {
 // sets those embedded structs up as interfaces in GraphQL.
 func (ob *ObjectBuilder) buildType(srcStruct interface{}) graphql.Type {
 	sType := reflect.TypeOf(srcStruct)
-	name := ob.prefix + sType.Name()
+	var embeds []string
+	for name := range extractEmbeds(srcStruct) {
+		embeds = append(embeds, name)
+	}
+	return ob.buildNamedType(sType.Name(), embeds, ob.structFields(sType))
+}
+
+// buildNamedType creates the GraphQL type for the struct with the given name whose embedded structs have the given
+// names, see buildType.
+func (ob *ObjectBuilder) buildNamedType(structName string, embeds []string, fields fieldsFunc) graphql.Type {
+	name := ob.prefix + structName
 
 	baseFields := make(graphql.Fields)
 	// Find any defined interfaces that are relevant for this struct
 	var gIfaces []*graphql.Interface
-	for name := range extractEmbeds(srcStruct) {
+	for _, name := range embeds {
 		if iface, ok := ob.interfaces[name]; ok {
 			gIfaces = append(gIfaces, iface)
 		}
@@ -209,10 +266,10 @@ func (ob *ObjectBuilder) buildType(srcStruct interface{}) graphql.Type {
 	}
 
 	if len(gIfaces) == 0 {
-		return ob.buildObject(sType, name, nil, nil)
+		return ob.newObject(name, nil, nil, fields)
 	}
 
-	object := ob.buildObject(sType, name, gIfaces, baseFields)
+	object := ob.newObject(name, gIfaces, baseFields, fields)
 	ob.objects[object.Name()] = object
 	return object
 }
@@ -224,9 +281,14 @@ func (ob *ObjectBuilder) buildType(srcStruct interface{}) graphql.Type {
 // that interface are expected to be provided as the fields for each type that implements an interface must match
 // exactly.
 func (ob *ObjectBuilder) buildObject(sType reflect.Type, name string, gInterfaces []*graphql.Interface, baseFields graphql.Fields) *graphql.Object {
+	return ob.newObject(name, gInterfaces, baseFields, ob.structFields(sType))
+}
+
+// newObject creates a GraphQL object just as buildObject with the fields built by the given fieldsFunc.
+func (ob *ObjectBuilder) newObject(name string, gInterfaces []*graphql.Interface, baseFields graphql.Fields, fields fieldsFunc) *graphql.Object {
 	name = strings.ToLower(name) // TODO for v2 consider removing this and the similar line in resolveObjectByName
 
-	gfields := ob.buildFields(sType, name, baseFields)
+	gfields := fields(name, baseFields)
 
 	cfg := graphql.ObjectConfig{
 		Name:       name,
@@ -290,37 +352,169 @@ func (ob *ObjectBuilder) buildFields(sType reflect.Type, parent string, baseFiel
 		if nn, ok := gtype.(*graphql.NonNull); ok {
 			checkType = nn.OfType
 		}
-		if _, ok := checkType.(*graphql.List); ok {
-			f.Args = graphql.FieldConfigArgument{
-				filterArgumentName: &graphql.ArgumentConfig{
-					Description: `A List Filter expression such as '{Field: "position", Operation: "<=", Argument: {Value: 10}}'`,
-					Type:        graphqlListFilter,
-				},
-				sortArgumentName: &graphql.ArgumentConfig{
-					Description: `Sort the list, ie '{Field: "position", Order: "ASC"}'`,
-					Type:        graphqlSortFilter,
-				},
-			}
-			f.Resolve = ResolveListField(name, parent)
-
-			totalName := "total" + strings.Title(name)
-			gfields[totalName] = &graphql.Field{
-				Name:        totalName,
-				Type:        graphql.Int,
-				Resolve:     ResolveTotalCount(totalName, name, parent),
-				Description: fmt.Sprintf("The total length of the %s list at this same level in the data, this number is unaffected by filtering.", name),
+		if list, ok := checkType.(*graphql.List); ok {
+			var paths []string
+			if ob.typedArguments {
+				paths = fieldPaths(listItemType(field.Type))
 			}
+			f = ob.buildListField(gfields, f, list, parent, paths, field.Tag.Get(aggregateTag) == "true")
 		}
 
 		gfields[name] = f
 	}
+	ob.addFieldAdditions(gfields, parent)
+
+	return gfields
+}
+
+// addFieldAdditions adds the fieldAdditions for the parent to gfields.
+func (ob *ObjectBuilder) addFieldAdditions(gfields graphql.Fields, parent string) {
 	if fields, ok := ob.fieldAdditions[parent]; ok {
 		for _, field := range fields {
 			gfields[field.Name] = field
 		}
 	}
+}
 
-	return gfields
+// buildListField sets up the list field f with the arguments and resolve function for a list, adds the total and end
+// cursor and optionally the filtered total, groups and aggregate fields for it to gfields and returns the field to use,
+// which is a connection with ConnectionFields. The paths are those of the fields within the list items, only used with
+// typed arguments, and aggregate is set if the field has the aggregate struct tag.
+func (ob *ObjectBuilder) buildListField(gfields graphql.Fields, f *graphql.Field, list *graphql.List, parent string, paths []string, aggregate bool) *graphql.Field {
+	name := f.Name
+	args := listFieldArguments()
+	var pathType graphql.Input = graphql.String
+	if ob.typedArguments {
+		args, pathType = ob.typedListFieldArguments(name, parent, paths)
+	}
+	f.Args = args
+	f.Resolve = ResolveListField(name, parent)
+
+	totalName := "total" + strings.Title(name)
+	filteredName := "filteredTotal" + strings.Title(name)
+	totalDescription := fmt.Sprintf("The total length of the %s list at this same level in the data, this number is unaffected by filtering.", name)
+	if ob.filteredCounts {
+		totalDescription = fmt.Sprintf("The total length of the %s list at this same level in the data, this number is unaffected by filtering, see %s.", name, filteredName)
+		gfields[filteredName] = &graphql.Field{
+			Name: filteredName,
+			Type: graphql.Int,
+			Args: graphql.FieldConfigArgument{
+				filterArgumentName: args[filterArgumentName],
+			},
+			Resolve:     ResolveFilteredCount(filteredName, name, parent),
+			Description: fmt.Sprintf("The number of items in the %s list at this same level in the data which match the filter.", name),
+		}
+	}
+	gfields[totalName] = &graphql.Field{
+		Name:        totalName,
+		Type:        graphql.Int,
+		Resolve:     ResolveTotalCount(totalName, name, parent),
+		Description: totalDescription,
+	}
+
+	cursorName := name + endCursorSuffix
+	gfields[cursorName] = &graphql.Field{
+		Name:        cursorName,
+		Type:        graphql.String,
+		Args:        args,
+		Resolve:     ResolveListEndCursor(cursorName, name, parent),
+		Description: fmt.Sprintf("The cursor of the last item in the page of the %s list with the same arguments, pass it as 'after' to get the next page.", name),
+	}
+
+	if ob.groups {
+		groupsName := name + groupsSuffix
+		gfields[groupsName] = &graphql.Field{
+			Name: groupsName,
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(ob.buildGroup(name, parent, list.OfType)))),
+			Args: graphql.FieldConfigArgument{
+				filterArgumentName: args[filterArgumentName],
+				sortArgumentName:   args[sortArgumentName],
+				groupByArgumentName: &graphql.ArgumentConfig{
+					Description: "The field of the list items to group by, the items themselves if not specified",
+					Type:        pathType,
+				},
+			},
+			Resolve:     ResolveListGroups(groupsName, name, parent),
+			Description: fmt.Sprintf("The items in the %s list which match the filter grouped by the value of a field.", name),
+		}
+	}
+
+	if ob.aggregates || aggregate {
+		aggregateName := name + aggregateSuffix
+		gfields[aggregateName] = &graphql.Field{
+			Name: aggregateName,
+			Type: graphql.NewNonNull(ob.aggregateObject()),
+			Args: graphql.FieldConfigArgument{
+				filterArgumentName: args[filterArgumentName],
+				aggregateFieldArgName: &graphql.ArgumentConfig{
+					Description: "The field of the list items to aggregate, the items themselves if not specified",
+					Type:        pathType,
+				},
+			},
+			Resolve:     ResolveListAggregate(aggregateName, name, parent),
+			Description: fmt.Sprintf("Aggregates of the items in the %s list which match the filter.", name),
+		}
+	}
+
+	if ob.connectionMode != ListFields {
+		connection := &graphql.Field{
+			Name:          name + connectionSuffix,
+			Type:          graphql.NewNonNull(ob.buildConnection(name, parent, list.OfType)),
+			Args:          args,
+			Resolve:       ResolveListConnection(name, parent),
+			ResolveSerial: true,
+			Description:   f.Description,
+		}
+		if ob.connectionMode == ConnectionFields {
+			connection.Name = name
+			f = connection
+		} else {
+			gfields[connection.Name] = connection
+		}
+	}
+
+	return f
+}
+
+// listFieldArguments returns the arguments for filtering, sorting and paging used by list fields.
+func listFieldArguments() graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		filterArgumentName: &graphql.ArgumentConfig{
+			Description: `A List Filter expression such as '{Field: "position", Operation: "<=", Argument: {Value: 10}}'`,
+			Type:        graphqlListFilter,
+		},
+		sortArgumentName: &graphql.ArgumentConfig{
+			Description: `Sort the list, ie '{Field: "position", Order: "ASC"}' or by multiple keys '[{Field: "priority", Order: "DESC"}, {Field: "position"}]'`,
+			Type:        graphqlSortFilter,
+		},
+		firstArgumentName: &graphql.ArgumentConfig{
+			Description: "Return at most this number of items, used with 'after' to page through the list",
+			Type:        graphql.Int,
+		},
+		afterArgumentName: &graphql.ArgumentConfig{
+			Description: "Return the items after the one with this opaque cursor, the cursor is only valid with the same filter and sort",
+			Type:        graphql.String,
+		},
+		offsetArgumentName: &graphql.ArgumentConfig{
+			Description: "Skip this number of items from the start of the list",
+			Type:        graphql.Int,
+		},
+		limitArgumentName: &graphql.ArgumentConfig{
+			Description: "Return at most this number of items, used with 'offset' to page through the list",
+			Type:        graphql.Int,
+		},
+	}
+}
+
+// listItemType returns the type of the items in a list type, pointers to the list are followed.
+func listItemType(rType reflect.Type) reflect.Type {
+	for rType.Kind() == reflect.Ptr {
+		rType = rType.Elem()
+	}
+	if rType.Kind() == reflect.Slice || rType.Kind() == reflect.Array {
+		return rType.Elem()
+	}
+	return rType
 }
 
 // fieldGraphQLType returns the graphql.Type which is appropriate for the kind of the struct field being examined.
@@ -378,9 +572,9 @@ func (ob *ObjectBuilder) graphQLType(rType reflect.Type, name, parent string) gr
 }
 
 // resolveObjectByName is a graphql.ResolveTypeFn used to determine the type a GraphQL interface resolves to based
-// on the name of the struct.
+// on the name of the struct, a pointer to a struct resolves to the type of the struct.
 func (ob *ObjectBuilder) resolveObjectByName(p graphql.ResolveTypeParams) *graphql.Object {
-	sType := reflect.TypeOf(p.Value)
+	sType, _ := structType(reflect.TypeOf(p.Value))
 	name := sType.Name()
 	name = ob.prefix + strings.ToLower(name) // TODO for v2 consider removing this and the similar line in buildObject
 	return ob.objects[name]
@@ -393,14 +587,58 @@ func (ob *ObjectBuilder) resolveObjectByName(p graphql.ResolveTypeParams) *graph
 // named 'Operation' and optional object field 'Argument' and string field named 'Field'. Certain operations may
 // require a valid 'Field' and/or 'Argument'. When provided 'Argument' should be a JSON object whose value will be
 // the argument to NewListOperation functions. The value of 'Field' is the name of a field in the list items, if
-// the list contains objects within it child field keys can be added using FieldPathSeparator.
+// the list contains objects within it child field keys can be added using FieldPathSeparator. An empty 'Field' refers
+// to the list item itself.
+//
+// Filters can be combined into boolean expressions, instead of 'Operation' a filter object can define exactly one of
+// 'And' or 'Or', each a list of filter objects, or 'Not', a single filter object. Groups can be nested to any depth,
+// ie '{And: [{Field: "type", Operation: "==", Argument: {Value: "story"}}, {Or: [...]}]}'.
+//
+// Operations found in ListTransforms, such as 'LIMIT', 'OFFSET', 'DISTINCT', 'REVERSE' and 'SAMPLE', work on the whole
+// list rather than each item. They can be the entire filter or children of a top level 'And' in which case they run
+// in order on the list after it has been filtered by the other children, ie '{And: [{Field: "type", Operation: "==",
+// Argument: {Value: "story"}}, {Operation: "OFFSET", Argument: {Value: 10}}, {Operation: "LIMIT", Argument:
+// {Value: 10}}]}'. The 'Field' of a transform is passed to it as the 'Field' argument, so '{Field: "type", Operation:
+// "DISTINCT"}' keeps the first item of each type.
+//
+// The 'IS NULL', 'IS NOT NULL' and 'EXISTS' operations take no argument and check whether the field is null or can be
+// found in each item. A field missing from a struct item itself is still an error, as for other operations, while one
+// missing deeper in the path or from a document item such as a map is treated as null.
+//
+// Fields which are themselves lists can be filtered with the 'ANY', 'ALL' and 'NONE' operations, the argument 'Filter'
+// is a nested filter applied to each item of the child list, ie
+// '{Field: "tags", Operation: "ANY", Argument: {Filter: {Field: "name", Operation: "==", Argument: {Value: "a"}}}}'.
+// The 'LENGTH' operation compares the length of a child list, ie '{Field: "tags", Operation: "LENGTH",
+// Argument: {Operation: ">", Value: 2}}'.
 //
 // In addition to the filter argument as sort argument can be specified. The sort argument takes a string parameter
 // Field which is the same as that for the filter, the field to be compared or the list itself if unspecified.
 // It also takes an optional order parameter which is either "ASC" or "DESC", "ASC" is default.
+// Items with a null value or missing the field are placed last in ascending order and first in descending order, this
+// can be changed with the optional Nulls parameter which is either "FIRST" or "LAST", ie
+// '{Field: "position", Order: "ASC", Nulls: "FIRST"}'.
+// The sort argument can also be a list of these sort objects, the list is sorted by the first with each following
+// sort used to order items which are equal for all those before it, ie
+// '[{Field: "priority", Order: "DESC"}, {Field: "position"}]'. Sorting is stable so equal items keep their order.
+// Strings, booleans, numbers of any size, times and types built on these can be sorted as can types which implement
+// Sortable, every item must have the same kind of value for a field though integers and floats are compared as numbers.
 //
 // Sorting occurs before filtering as some filters limit the total returned size of the list.
 //
+// The list can be paged through with either the 'offset' and 'limit' arguments or the 'first' and 'after' arguments,
+// paging happens after sorting and filtering. The 'after' argument is an opaque cursor, it is only valid with the same
+// filter and sort as the list it came from. The cursor of the last item in a page is resolved by the '<name>EndCursor'
+// field, see ResolveListEndCursor, given the same arguments as the list field, ie
+// 'modules(sort: $s, first: 10) { ... } modulesEndCursor(sort: $s, first: 10)'. For a sorted list the cursor holds the
+// sort values of the item so the next page starts after it even if items are added or removed earlier in the list,
+// for a list which isn't sorted it holds the position of the item.
+//
+// Both the filter and sort can be given inline or as query variables of type ListFilter and SortFilter, ie
+// 'query($f: ListFilter) { ... modules(filter: $f) ... }', a variable is decoded from JSON to the same filter or sort.
+//
+// An invalid filter or sort results in a FilterError or SortError, with an ErrorCollector in the context their details
+// can be added to the GraphQL errors as extensions.
+//
 // Example:
 //
 //	{
@@ -411,103 +649,184 @@ func (ob *ObjectBuilder) resolveObjectByName(p graphql.ResolveTypeParams) *graph
 //	  }
 //	}
 func ResolveListField(name string, parent string) graphql.FieldResolveFn {
-	return func(p graphql.ResolveParams) (interface{}, error) {
-		filter, err := newListFilter(p.Args[filterArgumentName])
+	return collectListErrors(func(p graphql.ResolveParams) (interface{}, error) {
+		args, err := parseListArguments(p, name, parent)
 		if err != nil {
 			return nil, err
 		}
 
-		sortParams, err := parseSortParameters(p.Args[sortArgumentName])
-		if err != nil {
-			return nil, err
-		}
-
-		if qr, ok := p.Context.Value(QueryReporterContextKey).(QueryFunctionReporter); ok && qr != nil {
-			var lf ListFunctions
-			if sortParams != nil {
-				lf.SortField = sortParams.field
-				lf.SortOrder = sortParams.order
-			}
-
-			if filter != nil {
-				lf.Filter = filter.json.String()
-			}
-
-			if err := qr.QueriedListFunctions(fmt.Sprintf("%s_%s", parent, name), lf); err != nil {
-				return nil, err
-			}
-		}
-
 		resolve := ResolveByField(name, parent)
 
 		resolvedValue, err := resolve(p)
 		if err != nil {
 			return nil, err
 		}
-		if filter == nil && sortParams == nil {
-			// an optimization, skip further processing if neither filtering nor sorting is specified
+		if resolvedValue == nil || args.filter == nil && args.sort == nil && args.page == nil {
+			// an optimization, skip further processing if there is no list or neither filtering, sorting nor paging is
+			// specified
 			return resolvedValue, nil
 		}
 
-		value := reflect.ValueOf(resolvedValue)
-		if value.Kind() != reflect.Slice {
-			return nil, fmt.Errorf("value returned from field %q is not a list as expected", fullFieldName(name, parent))
+		values, err := args.sortAndFilter(resolvedValue, name, parent)
+		if err != nil {
+			return nil, err
 		}
 
-		values := make([]interface{}, value.Len())
-		for i := 0; i < value.Len(); i++ {
-			values[i] = value.Index(i).Interface()
+		if args.page != nil {
+			values, err = args.page.apply(args.cursors(values))
+			if err != nil {
+				return nil, err
+			}
 		}
 
-		// sort before filter because some filters are based on the count of items
-		if sortParams != nil {
-			if err := listSort(sortParams, values); err != nil {
-				return nil, err
+		return values, nil
+	})
+}
+
+// listArguments are the parsed filter, sort and paging arguments of a list field.
+type listArguments struct {
+	filter *listFilter
+	sort   []*sortParameters
+	page   *pageParameters
+	query  string // identifies the filter and sort in cursors, see cursorQuery
+}
+
+// parseListArguments parses the arguments of a list field and reports them to the QueryFunctionReporter if one is
+// found in the context.
+func parseListArguments(p graphql.ResolveParams, name, parent string) (*listArguments, error) {
+	filter, err := newListFilter(p.Args[filterArgumentName])
+	if err != nil {
+		return nil, err
+	}
+
+	sortParams, err := parseSortParameters(p.Args[sortArgumentName])
+	if err != nil {
+		return nil, err
+	}
+
+	query := cursorQuery(filter, sortParams)
+	page, err := parsePageParameters(p.Args, sortParams, query)
+	if err != nil {
+		return nil, err
+	}
+
+	if qr, ok := p.Context.Value(QueryReporterContextKey).(QueryFunctionReporter); ok && qr != nil {
+		var lf ListFunctions
+		for i, params := range sortParams {
+			if i == 0 {
+				lf.SortField = params.field
+				lf.SortOrder = params.order
 			}
+			lf.SortKeys = append(lf.SortKeys, ListSortKey{Field: params.field, Order: params.order, Nulls: params.nulls})
 		}
 
-		if filter == nil {
-			return values, nil
+		if filter != nil {
+			lf.Filter = filter.json.String()
 		}
 
-		var filtered []interface{}
-		for _, item := range values {
-			matched, err := filter.match(item)
-			if err != nil {
-				return nil, fmt.Errorf("%v. Note: filtering and sorting is not available on hydrated items", err)
-			}
-			if matched {
-				filtered = append(filtered, item)
-			}
+		if err := qr.QueriedListFunctions(fmt.Sprintf("%s_%s", parent, name), lf); err != nil {
+			return nil, err
 		}
+	}
 
-		return filtered, nil
+	return &listArguments{filter: filter, sort: sortParams, page: page, query: query}, nil
+}
+
+// cursors returns the listCursors for the filtered and sorted list items.
+func (args *listArguments) cursors(values []interface{}) *listCursors {
+	return newListCursors(values, args.sort, args.query)
+}
+
+// sortAndFilter returns the items of the resolved list value sorted and then filtered, paging is not applied.
+// A json.RawMessage item is decoded first so the comparators don't decode it for every field they look up.
+func (args *listArguments) sortAndFilter(resolvedValue interface{}, name, parent string) ([]interface{}, error) {
+	value := reflect.ValueOf(resolvedValue)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return nil, fmt.Errorf("value returned from field %q is not a list as expected", fullFieldName(name, parent))
+	}
+
+	values := make([]interface{}, value.Len())
+	for i := 0; i < value.Len(); i++ {
+		values[i] = jsonValue(value.Index(i).Interface())
+	}
+
+	// sort before filter because some filters are based on the count of items
+	if args.sort != nil {
+		if err := listSort(args.sort, values); err != nil {
+			return nil, err
+		}
+	}
+
+	if args.filter != nil {
+		var err error
+		values, err = args.filter.apply(values)
+		if err != nil {
+			return nil, fmt.Errorf("%w. Note: filtering and sorting is not available on hydrated items", err)
+		}
 	}
+
+	return values, nil
 }
 
 // ResolveByField returns a FieldResolveFn that leverages ExtractFields for the given field name to
 // resolve the data. The resolve function assumes the entire object is available in the ResolveParams source.
 // It will also report the queried field to the QueryReporter if one is found in the context.
+// The source may be a struct or a pointer to one, a field holding a nil pointer resolves to null. The source may also
+// be a decoded JSON document as described for ExtractField, a missing or null document field resolves to null and a
+// string is parsed as RFC 3339 for a DateTime field. A source implementing FieldResolver resolves its own fields, a
+// field it has with a nil value resolves to null. A json.RawMessage value from a document or FieldResolver, or within
+// a list value, is decoded once here so child fields are resolved from the decoded document.
 // This is default resolve function used by the objectbuilder.
 func ResolveByField(name string, parent string) graphql.FieldResolveFn {
 	return func(p graphql.ResolveParams) (interface{}, error) {
-		if qr, ok := p.Context.Value(QueryReporterContextKey).(QueryReporter); ok && qr != nil {
-			if err := qr.QueriedField(fullFieldName(name, parent)); err != nil {
-				return nil, err
-			}
+		if err := ReportQueriedField(p, name, parent); err != nil {
+			return nil, err
 		}
 
-		field := ExtractField(p.Source, name)
+		source := jsonValue(p.Source)
+		field, found := lookupField(source, name)
+		if isDocument(source) {
+			return documentField(p, field)
+		}
+		if _, ok := p.Source.(FieldResolver); ok {
+			if found && field == nil {
+				return nil, nil
+			}
+			field = decodeJSON(field)
+		}
 		if field == nil {
 			return nil, graphql.NewLocatedError(
 				fmt.Errorf("failed to extract field %q value from data", name),
 				graphql.FieldASTsToNodeASTs(p.Info.FieldASTs),
 			)
 		}
+		if value := reflect.ValueOf(field); value.Kind() == reflect.Ptr && value.IsNil() {
+			// A nil pointer is resolved as null rather than an object whose fields can't be extracted
+			return nil, nil
+		}
 		return field, nil
 	}
 }
 
+// documentField returns the value of a field extracted from a decoded JSON document. A document can omit a field or
+// set it to null, either way it resolves to null. A json.RawMessage value or list item is decoded, see decodeJSON.
+// JSON has no time type so a string is parsed for a DateTime field.
+func documentField(p graphql.ResolveParams, field interface{}) (interface{}, error) {
+	field = decodeJSON(field)
+	text, ok := field.(string)
+	if !ok || graphql.GetNamed(p.Info.ReturnType) != graphql.DateTime {
+		return field, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, text)
+	if err != nil {
+		return nil, graphql.NewLocatedError(
+			fmt.Errorf("failed to parse field %q value as a DateTime: %v", p.Info.FieldName, err),
+			graphql.FieldASTsToNodeASTs(p.Info.FieldASTs),
+		)
+	}
+	return t, nil
+}
+
 // ResolveTotalCount accepts a total count field name, a name of the list field, and a parent name. It leverages
 // ExtractField for the given list field name and will return the count of items in the extract field if it is an array
 // or a slice. It will also report the queried field to the QueryReporter if one is found in the context.
@@ -536,6 +855,55 @@ func ResolveTotalCount(totalFieldName, listFieldName, parent string) graphql.Fie
 	}
 }
 
+// SetFilteredCountFields sets whether a field named 'filteredTotal<Name>' is built next to every list field to count
+// the items matching a filter, by default it is not. This must be called before BuildInterfaces or BuildTypes.
+func (ob *ObjectBuilder) SetFilteredCountFields(enabled bool) {
+	ob.filteredCounts = enabled
+}
+
+// ResolveFilteredCount accepts a count field name, a name of the list field, and a parent name. It works as
+// ResolveTotalCount but returns the count of items in the list which match the optional filter argument, the filter is
+// the same as that of ResolveListField. It will also report the queried field to the QueryReporter and the filter to
+// the QueryFunctionReporter if found in the context.
+func ResolveFilteredCount(countFieldName, listFieldName, parent string) graphql.FieldResolveFn {
+	return collectListErrors(func(p graphql.ResolveParams) (interface{}, error) {
+		if qr, ok := p.Context.Value(QueryReporterContextKey).(QueryReporter); ok && qr != nil {
+			if err := qr.QueriedField(fullFieldName(countFieldName, parent)); err != nil {
+				return nil, err
+			}
+		}
+
+		args, err := parseListArguments(p, countFieldName, parent)
+		if err != nil {
+			return nil, err
+		}
+
+		values, err := filteredListItems(p, args, listFieldName, parent)
+		if err != nil {
+			return nil, err
+		}
+		return len(values), nil
+	})
+}
+
+// filteredListItems extracts the list field from the source and returns the items matching the filter in the
+// arguments. A list field which doesn't exist in the source has no items.
+func filteredListItems(p graphql.ResolveParams, args *listArguments, listFieldName, parent string) ([]interface{}, error) {
+	field := ExtractField(p.Source, listFieldName)
+	fieldValue := reflect.ValueOf(field)
+	if !fieldValue.IsValid() {
+		// This will happen when the field doesn't exist at all in the resolved interface
+		return nil, nil
+	}
+	if fieldValue.Kind() != reflect.Slice && fieldValue.Kind() != reflect.Array {
+		return nil, graphql.NewLocatedError(
+			fmt.Errorf("field value is not a valid list in the data"),
+			graphql.FieldASTsToNodeASTs(p.Info.FieldASTs),
+		)
+	}
+	return args.sortAndFilter(field, listFieldName, parent)
+}
+
 // findObjectField traverses the fields in the given GraphQL object and returns the value of the one matching the path.
 // If the path contains multiple items it is assumed that each item represents a layer in a nested set of objects.
 // This only handles fields that are themselves graphql.Objects other field types are ignored.
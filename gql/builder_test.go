@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -61,11 +62,26 @@ type testDoubleEmbed struct {
 	Extra string
 }
 
+// testFieldResolver is a FieldResolver holding its fields in a map and counting the fields resolved.
+type testFieldResolver struct {
+	fields   map[string]interface{}
+	resolved *int32
+}
+
+func (r testFieldResolver) ResolveGraphQLField(name string) (interface{}, bool) {
+	if r.resolved != nil {
+		atomic.AddInt32(r.resolved, 1)
+	}
+	value, ok := r.fields[name]
+	return value, ok
+}
+
 type testQueryReporter struct {
 	reporterMux  sync.Mutex
 	queriedField string
 	sortField    string
 	sortOrder    string
+	sortKeys     []ListSortKey
 	filter       string
 }
 
@@ -80,6 +96,7 @@ func (tqr *testQueryReporter) QueriedListFunctions(field string, lf ListFunction
 	tqr.reporterMux.Lock()
 	tqr.sortField = lf.SortField
 	tqr.sortOrder = lf.SortOrder
+	tqr.sortKeys = lf.SortKeys
 	tqr.filter = lf.Filter
 	tqr.reporterMux.Unlock()
 	return nil
@@ -241,6 +258,384 @@ func TestGeneratedSchema(t *testing.T) {
 	}
 }
 
+func TestPointerSources(t *testing.T) {
+	type leaf struct {
+		Name string
+	}
+	type item struct {
+		*TestBase
+		Name   string
+		Value  int
+		Parent *leaf `json:"parent,omitempty"`
+	}
+	type testStruct struct {
+		TestBase
+		Items []*item
+		Leaf  *leaf
+	}
+	testData := &testStruct{
+		TestBase: TestBase{Id: "id"},
+		Items: []*item{
+			{TestBase: &TestBase{Id: "c"}, Name: "c", Value: 3, Parent: &leaf{Name: "y"}},
+			{Name: "a", Value: 1},
+			{TestBase: &TestBase{Id: "b"}, Name: "b", Value: 2, Parent: &leaf{Name: "x"}},
+		},
+		Leaf: &leaf{Name: "leaf"},
+	}
+
+	tests := []struct {
+		description string
+		query       string
+		want        string
+	}{
+		{
+			description: "Pointer root and nested pointer field",
+			query:       `query { q { id leaf { name } } }`,
+			want:        `{"data":{"q":{"id":"id","leaf":{"name":"leaf"}}}}`,
+		},
+		{
+			description: "Interface query",
+			query:       `query { i { id ... on teststruct { leaf { name } } } }`,
+			want:        `{"data":{"i":{"id":"id","leaf":{"name":"leaf"}}}}`,
+		},
+		{
+			description: "List of pointers with a nil pointer field",
+			query:       `query { q { totalItems items { name parent { name } } } }`,
+			want:        `{"data":{"q":{"items":[{"name":"c","parent":{"name":"y"}},{"name":"a","parent":null},{"name":"b","parent":{"name":"x"}}],"totalItems":3}}}`,
+		},
+		{
+			description: "Filter and sort a list of pointers",
+			query:       `query { q { items(filter: {Field: "value", Operation: ">", Argument: {Value: 1}}, sort: {Field: "parent_name"}) { name } } }`,
+			want:        `{"data":{"q":{"items":[{"name":"b"},{"name":"c"}]}}}`,
+		},
+		{
+			description: "Filter on a field of an embedded pointer",
+			query:       `query { q { items(filter: {Field: "id", Operation: "IS NULL"}) { name } } }`,
+			want:        `{"data":{"q":{"items":[{"name":"a"}]}}}`,
+		},
+	}
+
+	ob, err := NewObjectBuilder([]interface{}{testStruct{}}, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interfaces := ob.BuildInterfaces()
+	types := ob.BuildTypes()
+	resolve := func(p graphql.ResolveParams) (interface{}, error) {
+		return testData, nil
+	}
+	s, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"q": &graphql.Field{Type: types[0], Resolve: resolve},
+				"i": &graphql.Field{Type: interfaces["TestBase"], Resolve: resolve},
+			},
+		}),
+		Types: types,
+	})
+	if err != nil {
+		t.Fatalf("failed to build schema: %v", err)
+	}
+
+	for _, test := range tests {
+		resp := graphql.Do(graphql.Params{Context: context.Background(), Schema: s, RequestString: test.query})
+		if len(resp.Errors) != 0 {
+			t.Errorf("Test %q - query failed: %v", test.description, resp.Errors)
+			continue
+		}
+
+		got, err := json.Marshal(resp)
+		if err != nil {
+			t.Errorf("Test %q - failed to Marshal: %v", test.description, err)
+		}
+		if string(got) != test.want {
+			t.Errorf("Test %q - got response %s, want %s", test.description, got, test.want)
+		}
+	}
+}
+
+func TestDocumentSources(t *testing.T) {
+	type leaf struct {
+		Name string `json:"name"`
+	}
+	type item struct {
+		Name      string    `json:"name"`
+		Value     int       `json:"value"`
+		Published time.Time `json:"published"`
+		Parent    *leaf     `json:"parent,omitempty"`
+	}
+	type testStruct struct {
+		ID    string   `json:"id"`
+		Title string   `json:"title,omitempty"`
+		Items []item   `json:"items"`
+		Tags  []string `json:"tags,omitempty"`
+	}
+	document := `{
+  "id": "doc",
+  "items": [
+    {"name": "c", "value": 3, "published": "2019-10-03T00:00:00Z", "parent": {"name": "y"}},
+    {"name": "a", "value": 1, "published": "2019-10-01T00:00:00Z"},
+    {"name": "b", "value": 2, "published": "2019-10-02T00:00:00Z", "parent": null}
+  ]
+}`
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(document), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	var rawItems struct {
+		ID    string            `json:"id"`
+		Items []json.RawMessage `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(document), &rawItems); err != nil {
+		t.Fatal(err)
+	}
+	withRawItems := map[string]interface{}{"id": rawItems.ID, "items": rawItems.Items}
+
+	tests := []struct {
+		description string
+		query       string
+		want        string
+	}{
+		{
+			description: "Fields, missing fields and DateTime",
+			query:       `query { q { id title tags items { name published parent { name } } } }`,
+			want:        `{"data":{"q":{"id":"doc","items":[{"name":"c","parent":{"name":"y"},"published":"2019-10-03T00:00:00Z"},{"name":"a","parent":null,"published":"2019-10-01T00:00:00Z"},{"name":"b","parent":null,"published":"2019-10-02T00:00:00Z"}],"tags":null,"title":null}}}`,
+		},
+		{
+			description: "Filter, sort and total",
+			query:       `query { q { totalItems filteredTotalItems(filter: {Field: "value", Operation: ">=", Argument: {Value: 2}}) items(filter: {Field: "value", Operation: ">=", Argument: {Value: 2}}, sort: {Field: "value"}) { name value } } }`,
+			want:        `{"data":{"q":{"filteredTotalItems":2,"items":[{"name":"b","value":2},{"name":"c","value":3}],"totalItems":3}}}`,
+		},
+		{
+			description: "Filter on a nested field and a missing list",
+			query:       `query { q { items(filter: {Field: "parent_name", Operation: "IS NULL"}) { name } tags(sort: {}) } }`,
+			want:        `{"data":{"q":{"items":[{"name":"a"},{"name":"b"}],"tags":null}}}`,
+		},
+		{
+			description: "Presence filter on a field missing from some items",
+			query:       `query { q { items(filter: {Field: "parent", Operation: "EXISTS"}) { name } } }`,
+			want:        `{"data":{"q":{"items":[{"name":"c"},{"name":"b"}]}}}`,
+		},
+	}
+
+	ob, err := NewObjectBuilder([]interface{}{testStruct{}}, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ob.SetFilteredCountFields(true)
+	types := ob.BuildTypes()
+
+	for _, source := range []interface{}{decoded, json.RawMessage(document), withRawItems} {
+		s, err := graphql.NewSchema(graphql.SchemaConfig{
+			Query: graphql.NewObject(graphql.ObjectConfig{
+				Name: "Query",
+				Fields: graphql.Fields{
+					"q": &graphql.Field{
+						Type: types[0],
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							return source, nil
+						},
+					},
+				},
+			}),
+			Types: types,
+		})
+		if err != nil {
+			t.Fatalf("failed to build schema: %v", err)
+		}
+
+		for _, test := range tests {
+			resp := graphql.Do(graphql.Params{Context: context.Background(), Schema: s, RequestString: test.query})
+			if len(resp.Errors) != 0 {
+				t.Errorf("Test %q with %T - query failed: %v", test.description, source, resp.Errors)
+				continue
+			}
+
+			got, err := json.Marshal(resp)
+			if err != nil {
+				t.Errorf("Test %q with %T - failed to Marshal: %v", test.description, source, err)
+			}
+			if string(got) != test.want {
+				t.Errorf("Test %q with %T - got response %s, want %s", test.description, source, got, test.want)
+			}
+		}
+	}
+}
+
+func TestFieldResolverSources(t *testing.T) {
+	type leaf struct {
+		Name string
+	}
+	type item struct {
+		Name   string
+		Value  int
+		Parent *leaf `json:"parent,omitempty"`
+	}
+	type testStruct struct {
+		ID    string `json:"id"`
+		Items []item
+	}
+
+	var resolved int32
+	newResolver := func(fields map[string]interface{}) testFieldResolver {
+		return testFieldResolver{fields: fields, resolved: &resolved}
+	}
+	source := newResolver(map[string]interface{}{
+		"id": "id",
+		"items": []interface{}{
+			newResolver(map[string]interface{}{"name": "c", "value": 3, "parent": newResolver(map[string]interface{}{"name": "y"})}),
+			newResolver(map[string]interface{}{"name": "a", "value": 1, "parent": nil}),
+			newResolver(map[string]interface{}{"name": "b", "value": 2, "parent": newResolver(map[string]interface{}{"name": "x"})}),
+		},
+	})
+
+	tests := []struct {
+		description  string
+		query        string
+		want         string
+		wantResolved int32
+	}{
+		{
+			description:  "Fields and null field",
+			query:        `query { q { id items { name parent { name } } } }`,
+			want:         `{"data":{"q":{"id":"id","items":[{"name":"c","parent":{"name":"y"}},{"name":"a","parent":null},{"name":"b","parent":{"name":"x"}}]}}}`,
+			wantResolved: 10,
+		},
+		{
+			description:  "Filter and sort",
+			query:        `query { q { items(filter: {Field: "value", Operation: ">", Argument: {Value: 1}}, sort: {Field: "parent_name", Order: "DESC"}) { name } } }`,
+			want:         `{"data":{"q":{"items":[{"name":"c"},{"name":"b"}]}}}`,
+			wantResolved: 11,
+		},
+	}
+
+	ob, err := NewObjectBuilder([]interface{}{testStruct{}}, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	types := ob.BuildTypes()
+	s, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"q": &graphql.Field{
+					Type: types[0],
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return source, nil
+					},
+				},
+			},
+		}),
+		Types: types,
+	})
+	if err != nil {
+		t.Fatalf("failed to build schema: %v", err)
+	}
+
+	for _, test := range tests {
+		atomic.StoreInt32(&resolved, 0)
+		resp := graphql.Do(graphql.Params{Context: context.Background(), Schema: s, RequestString: test.query})
+		if len(resp.Errors) != 0 {
+			t.Errorf("Test %q - query failed: %v", test.description, resp.Errors)
+			continue
+		}
+
+		got, err := json.Marshal(resp)
+		if err != nil {
+			t.Errorf("Test %q - failed to Marshal: %v", test.description, err)
+		}
+		if string(got) != test.want {
+			t.Errorf("Test %q - got response %s, want %s", test.description, got, test.want)
+		}
+		if got := atomic.LoadInt32(&resolved); got != test.wantResolved {
+			t.Errorf("Test %q - got %d fields resolved, want %d", test.description, got, test.wantResolved)
+		}
+	}
+}
+
+func TestOptionalListFields(t *testing.T) {
+	type testStruct struct {
+		Items []string
+	}
+	testData := testStruct{Items: []string{"a", "b", "a"}}
+
+	tests := []struct {
+		description string
+		configure   func(ob *ObjectBuilder)
+		query       string
+		want        string
+		wantErr     bool
+	}{
+		{
+			description: "Filtered count",
+			configure:   func(ob *ObjectBuilder) { ob.SetFilteredCountFields(true) },
+			query:       `query { q { filteredTotalItems(filter: {Operation: "==", Argument: {Value: "a"}}) } }`,
+			want:        `{"data":{"q":{"filteredTotalItems":2}}}`,
+		},
+		{
+			description: "Filtered count not enabled",
+			configure:   func(ob *ObjectBuilder) {},
+			query:       `query { q { filteredTotalItems } }`,
+			wantErr:     true,
+		},
+		{
+			description: "Groups",
+			configure:   func(ob *ObjectBuilder) { ob.SetGroupFields(true) },
+			query:       `query { q { itemsGroups { key count } } }`,
+			want:        `{"data":{"q":{"itemsGroups":[{"count":2,"key":"a"},{"count":1,"key":"b"}]}}}`,
+		},
+		{
+			description: "Groups not enabled",
+			configure:   func(ob *ObjectBuilder) {},
+			query:       `query { q { itemsGroups { key } } }`,
+			wantErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		ob, err := NewObjectBuilder([]interface{}{testStruct{}}, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		test.configure(ob)
+		types := ob.BuildTypes()
+		s, err := graphql.NewSchema(graphql.SchemaConfig{
+			Query: graphql.NewObject(graphql.ObjectConfig{
+				Name: "Query",
+				Fields: graphql.Fields{
+					"q": &graphql.Field{
+						Type: types[0],
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							return testData, nil
+						},
+					},
+				},
+			}),
+			Types: types,
+		})
+		if err != nil {
+			t.Fatalf("Test %q - failed to build schema: %v", test.description, err)
+		}
+
+		resp := graphql.Do(graphql.Params{Context: context.Background(), Schema: s, RequestString: test.query})
+		if gotErr := len(resp.Errors) != 0; gotErr != test.wantErr {
+			t.Errorf("Test %q - got errors %v, want error %t", test.description, resp.Errors, test.wantErr)
+		}
+		if test.wantErr {
+			continue
+		}
+
+		got, err := json.Marshal(resp)
+		if err != nil {
+			t.Errorf("Test %q - failed to Marshal: %v", test.description, err)
+		}
+		if string(got) != test.want {
+			t.Errorf("Test %q - got response %s, want %s", test.description, got, test.want)
+		}
+	}
+}
+
 func TestObjectBuilder_BuildInterfaces(t *testing.T) {
 	tests := []struct {
 		description string
@@ -764,6 +1159,16 @@ func TestResolveByField(t *testing.T) {
 			source:      tb,
 			wantErr:     true,
 		},
+		{
+			description: "Pointer found test",
+			source:      &te,
+			want:        extra,
+		},
+		{
+			description: "Nil pointer test",
+			source:      (*testEmbed)(nil),
+			wantErr:     true,
+		},
 		{
 			description:      "found with queried field",
 			source:           te,
@@ -871,6 +1276,7 @@ func TestResolveObjectByName(t *testing.T) {
 	}
 
 	type ObjectA string
+	objectAValue := ObjectA("")
 
 	tests := []struct {
 		description string
@@ -882,6 +1288,11 @@ func TestResolveObjectByName(t *testing.T) {
 			value:       ObjectA(""),
 			want:        objectA,
 		},
+		{
+			description: "pointer found",
+			value:       &objectAValue,
+			want:        objectA,
+		},
 		{
 			description: "not found",
 			value:       childA,
@@ -0,0 +1,180 @@
+package gql
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+const (
+	booleanCategory = "boolean"
+	numberCategory  = "number"
+	stringCategory  = "string"
+)
+
+// A TypeChecker is a Comparator which can determine if a value is of a type it could ever match.
+// When the Comparator for a filter operation implements TypeChecker the value of each list item field is checked
+// before matching and any error is returned from the filter rather than the item being silently excluded.
+type TypeChecker interface {
+	// CheckType returns an error if the value is of a type which can never match. Nil values are never an error.
+	CheckType(interface{}) error
+}
+
+// coerceValue converts a value to the canonical type used for comparisons, this is a bool, string, int64, uint64 or
+// float64. All widths of integers and floats along with named types built on these kinds are converted, pointers
+// are followed. Unsigned integers are converted to an int64 unless they are too large.
+// False is returned if the value is nil or not one of the supported kinds.
+func coerceValue(raw interface{}) (interface{}, bool) {
+	// The common types are handled without reflection
+	switch v := raw.(type) {
+	case nil:
+		return nil, false
+	case string:
+		return v, true
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	case float64:
+		return v, true
+	case bool:
+		return v, true
+	}
+
+	value := reflect.ValueOf(raw)
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil, false
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Bool:
+		return value.Bool(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := value.Uint()
+		if u <= math.MaxInt64 {
+			return int64(u), true
+		}
+		return u, true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	case reflect.String:
+		return value.String(), true
+	default:
+		return nil, false
+	}
+}
+
+// valueCategory returns the category of a canonical value as returned by coerceValue, values of different categories
+// can never be equal.
+func valueCategory(value interface{}) string {
+	switch value.(type) {
+	case bool:
+		return booleanCategory
+	case string:
+		return stringCategory
+	case int64, uint64, float64:
+		return numberCategory
+	default:
+		return ""
+	}
+}
+
+// valuesEqual compares two canonical values as returned by coerceValue. Numbers are compared numerically regardless of
+// their type.
+func valuesEqual(a, b interface{}) bool {
+	switch a := a.(type) {
+	case bool:
+		b, ok := b.(bool)
+		return ok && a == b
+	case string:
+		b, ok := b.(string)
+		return ok && a == b
+	case int64, uint64, float64:
+		c, ok := compareNumbers(a, b)
+		return ok && c == 0
+	default:
+		return false
+	}
+}
+
+// compareNumbers compares two canonical numbers as returned by coerceValue, the result is negative if a is less than
+// b, positive if a is greater than b and 0 if they are equal. False is returned if either is not a number or is NaN.
+func compareNumbers(a, b interface{}) (int, bool) {
+	switch a := a.(type) {
+	case int64:
+		switch b := b.(type) {
+		case int64:
+			return compareInt64(a, b), true
+		case uint64:
+			// a uint64 canonical value is always larger than any int64
+			return -1, true
+		case float64:
+			return compareFloatNumbers(float64(a), b)
+		}
+	case uint64:
+		switch b := b.(type) {
+		case int64:
+			return 1, true
+		case uint64:
+			switch {
+			case a < b:
+				return -1, true
+			case a > b:
+				return 1, true
+			}
+			return 0, true
+		case float64:
+			return compareFloatNumbers(float64(a), b)
+		}
+	case float64:
+		switch b := b.(type) {
+		case int64:
+			return compareFloatNumbers(a, float64(b))
+		case uint64:
+			return compareFloatNumbers(a, float64(b))
+		case float64:
+			return compareFloatNumbers(a, b)
+		}
+	}
+	return 0, false
+}
+
+func compareFloatNumbers(a, b float64) (int, bool) {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return 0, false
+	}
+	return compareFloat64(a, b), true
+}
+
+// checkCategory returns an error if the raw value is not nil and either can't be coerced or is not in the category.
+func checkCategory(raw interface{}, category string) error {
+	if isNil(raw) {
+		return nil
+	}
+	value, ok := coerceValue(raw)
+	if !ok {
+		return fmt.Errorf("a value of type %T can not be compared with a %s", raw, category)
+	}
+	if valueCategory(value) != category {
+		return fmt.Errorf("a %s value of type %T can never match a %s", valueCategory(value), raw, category)
+	}
+	return nil
+}
+
+// isNil returns true if the value is nil or a nil pointer.
+func isNil(raw interface{}) bool {
+	if raw == nil {
+		return true
+	}
+	value := reflect.ValueOf(raw)
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		return value.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,252 @@
+package gql
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCoerceValue(t *testing.T) {
+	var nilInt *int
+	i := 3
+
+	tests := []struct {
+		description string
+		in          interface{}
+		want        interface{}
+		wantOK      bool
+	}{
+		{
+			description: "nil",
+			in:          nil,
+		},
+		{
+			description: "nil pointer",
+			in:          nilInt,
+		},
+		{
+			description: "pointer",
+			in:          &i,
+			want:        int64(3),
+			wantOK:      true,
+		},
+		{
+			description: "int",
+			in:          -1,
+			want:        int64(-1),
+			wantOK:      true,
+		},
+		{
+			description: "int8",
+			in:          int8(-8),
+			want:        int64(-8),
+			wantOK:      true,
+		},
+		{
+			description: "int32",
+			in:          int32(32),
+			want:        int64(32),
+			wantOK:      true,
+		},
+		{
+			description: "uint",
+			in:          uint(1),
+			want:        int64(1),
+			wantOK:      true,
+		},
+		{
+			description: "uint64 larger than max int64",
+			in:          uint64(math.MaxUint64),
+			want:        uint64(math.MaxUint64),
+			wantOK:      true,
+		},
+		{
+			description: "float32",
+			in:          float32(0.5),
+			want:        0.5,
+			wantOK:      true,
+		},
+		{
+			description: "bool",
+			in:          true,
+			want:        true,
+			wantOK:      true,
+		},
+		{
+			description: "named string",
+			in:          testKind("story"),
+			want:        "story",
+			wantOK:      true,
+		},
+		{
+			description: "named uint8",
+			in:          testPriority(2),
+			want:        int64(2),
+			wantOK:      true,
+		},
+		{
+			description: "named bool",
+			in:          testFlag(true),
+			want:        true,
+			wantOK:      true,
+		},
+		{
+			description: "slice",
+			in:          []int{1},
+		},
+		{
+			description: "struct",
+			in:          struct{}{},
+		},
+	}
+
+	for _, test := range tests {
+		got, ok := coerceValue(test.in)
+		if ok != test.wantOK {
+			t.Errorf("Test %q - got ok %t, want %t", test.description, ok, test.wantOK)
+		}
+		if got != test.want {
+			t.Errorf("Test %q - got %#v, want %#v", test.description, got, test.want)
+		}
+	}
+}
+
+func TestCompareNumbers(t *testing.T) {
+	tests := []struct {
+		description string
+		a           interface{}
+		b           interface{}
+		want        int
+		wantOK      bool
+	}{
+		{
+			description: "int64 less",
+			a:           int64(-1),
+			b:           int64(1),
+			want:        -1,
+			wantOK:      true,
+		},
+		{
+			description: "int64 equal",
+			a:           int64(1),
+			b:           int64(1),
+			want:        0,
+			wantOK:      true,
+		},
+		{
+			description: "int64 and uint64",
+			a:           int64(math.MaxInt64),
+			b:           uint64(math.MaxUint64),
+			want:        -1,
+			wantOK:      true,
+		},
+		{
+			description: "uint64 and int64",
+			a:           uint64(math.MaxUint64),
+			b:           int64(math.MaxInt64),
+			want:        1,
+			wantOK:      true,
+		},
+		{
+			description: "uint64 equal",
+			a:           uint64(math.MaxUint64),
+			b:           uint64(math.MaxUint64),
+			want:        0,
+			wantOK:      true,
+		},
+		{
+			description: "int64 and float64",
+			a:           int64(4),
+			b:           4.5,
+			want:        -1,
+			wantOK:      true,
+		},
+		{
+			description: "float64 and int64 equal",
+			a:           2.0,
+			b:           int64(2),
+			want:        0,
+			wantOK:      true,
+		},
+		{
+			description: "float64 and uint64",
+			a:           math.Inf(1),
+			b:           uint64(math.MaxUint64),
+			want:        1,
+			wantOK:      true,
+		},
+		{
+			description: "NaN",
+			a:           math.NaN(),
+			b:           1.0,
+		},
+		{
+			description: "not a number",
+			a:           int64(1),
+			b:           "1",
+		},
+		{
+			description: "not a canonical number",
+			a:           1,
+			b:           int64(1),
+		},
+	}
+
+	for _, test := range tests {
+		got, ok := compareNumbers(test.a, test.b)
+		if ok != test.wantOK {
+			t.Errorf("Test %q - got ok %t, want %t", test.description, ok, test.wantOK)
+		}
+		if got != test.want {
+			t.Errorf("Test %q - got %d, want %d", test.description, got, test.want)
+		}
+	}
+}
+
+func TestValuesEqual(t *testing.T) {
+	tests := []struct {
+		description string
+		a           interface{}
+		b           interface{}
+		want        bool
+	}{
+		{
+			description: "strings equal",
+			a:           "a",
+			b:           "a",
+			want:        true,
+		},
+		{
+			description: "string and bool",
+			a:           "true",
+			b:           true,
+		},
+		{
+			description: "bools equal",
+			a:           false,
+			b:           false,
+			want:        true,
+		},
+		{
+			description: "int64 and float64 equal",
+			a:           int64(3),
+			b:           3.0,
+			want:        true,
+		},
+		{
+			description: "int64 and string",
+			a:           int64(3),
+			b:           "3",
+		},
+		{
+			description: "unsupported type",
+			a:           []int{1},
+			b:           []int{1},
+		},
+	}
+
+	for _, test := range tests {
+		if got := valuesEqual(test.a, test.b); got != test.want {
+			t.Errorf("Test %q - got %t, want %t", test.description, got, test.want)
+		}
+	}
+}
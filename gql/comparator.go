@@ -4,7 +4,38 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
+	"regexp/syntax"
+	"strings"
 	"sync"
+	"time"
+)
+
+const (
+	stringContains   = "CONTAINS"
+	stringStartsWith = "STARTS WITH"
+	stringEndsWith   = "ENDS WITH"
+	stringEquals     = "=="
+	stringMatches    = "MATCHES"
+
+	stringEqualsIgnoreCase     = "EQUALS IGNORE CASE"
+	stringContainsIgnoreCase   = "CONTAINS IGNORE CASE"
+	stringStartsWithIgnoreCase = "STARTS WITH IGNORE CASE"
+	stringEndsWithIgnoreCase   = "ENDS WITH IGNORE CASE"
+
+	timeBefore  = "BEFORE"
+	timeAfter   = "AFTER"
+	timeBetween = "BETWEEN"
+
+	nullIs    = "IS NULL"
+	nullIsNot = "IS NOT NULL"
+	nullExist = "EXISTS"
+
+	quantifierAny  = "ANY"
+	quantifierAll  = "ALL"
+	quantifierNone = "NONE"
+
+	listLength = "LENGTH"
 )
 
 // A Comparator checks whether a value Matches.
@@ -13,6 +44,15 @@ type Comparator interface {
 	Match(interface{}) bool
 }
 
+// A PresenceComparator is a Comparator which also matches on whether the field it is given exists.
+// When the Comparator for a filter operation implements PresenceComparator MatchPresence is used in place of Match
+// and a nested field which can't be found, or a field missing from a document list item, is not an error.
+type PresenceComparator interface {
+	Comparator
+	// MatchPresence is given the value of the field and false for exists if the field path could not be found.
+	MatchPresence(value interface{}, exists bool) bool
+}
+
 // NotComparator simply does a logical not on the Match result of its child.
 type NotComparator struct {
 	Child Comparator
@@ -22,25 +62,61 @@ func (c NotComparator) Match(raw interface{}) bool {
 	return !c.Child.Match(raw)
 }
 
-// NewEqualComparator returns a comparator for equality of both strings and ints.
-// The comparator expects either a string or int with the key "Value" as part of the given argument.
+// CheckType implements TypeChecker by running the CheckType of the child if it is a TypeChecker.
+func (c NotComparator) CheckType(raw interface{}) error {
+	if tc, ok := c.Child.(TypeChecker); ok {
+		return tc.CheckType(raw)
+	}
+	return nil
+}
+
+// AndComparator does a logical and on the Match results of its children, it stops at the first child that does not
+// match.
+type AndComparator struct {
+	Children []Comparator
+}
+
+func (c AndComparator) Match(raw interface{}) bool {
+	for _, child := range c.Children {
+		if !child.Match(raw) {
+			return false
+		}
+	}
+	return true
+}
+
+// OrComparator does a logical or on the Match results of its children, it stops at the first child that matches.
+type OrComparator struct {
+	Children []Comparator
+}
+
+func (c OrComparator) Match(raw interface{}) bool {
+	for _, child := range c.Children {
+		if child.Match(raw) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewEqualComparator returns a comparator for equality of strings, numbers and booleans.
+// The comparator expects either a string, number or boolean with the key "Value" as part of the given argument.
+// Numbers are compared numerically so an int Value will match an equal field of any integer or float type, named
+// types such as 'type Kind string' are compared by their underlying value.
 func NewEqualComparator(arg map[string]interface{}) (Comparator, error) {
 	raw, ok := arg["Value"]
 	if !ok {
 		return nil, errors.New("filter argument is missing 'Value'")
 	}
-	switch a := raw.(type) {
-	case int:
-		return intEqual{value: a}, nil
-	case string:
-		return stringEqual{value: a}, nil
-	default:
-		return nil, errors.New("unsupported argument value, strings and integers are supported")
+	value, ok := coerceValue(raw)
+	if !ok {
+		return nil, errors.New("unsupported argument value, strings, numbers and booleans are supported")
 	}
+	return equalComparator{value: value}, nil
 }
 
-// NewNotEqualComparator returns a comparator for not equality of both strings and ints.
-// The comparator expects either a string or int with the key "Value" as part of the given argument.
+// NewNotEqualComparator returns a comparator for not equality of strings, numbers and booleans.
+// The comparator expects either a string, number or boolean with the key "Value" as part of the given argument.
 func NewNotEqualComparator(arg map[string]interface{}) (Comparator, error) {
 	eq, err := NewEqualComparator(arg)
 	if err != nil {
@@ -49,8 +125,37 @@ func NewNotEqualComparator(arg map[string]interface{}) (Comparator, error) {
 	return NotComparator{Child: eq}, nil
 }
 
+// NewLimitLengthComparator returns a comparator which simply limits the length of the results to the integer
+// given with the key "Value" in the argument. The comparator counts the items it is matched against so it must only
+// be used for a single list and it is not part of ListOperations.
+//
+// Deprecated: the 'LIMIT' operation is now a ListTransform, use NewLimitTransform which is applied after filtering and
+// sorting.
+func NewLimitLengthComparator(arg map[string]interface{}) (Comparator, error) {
+	limit, err := countArgument(arg, "Value")
+	if err != nil {
+		return nil, err
+	}
+	return &limitLength{limit: limit}, nil
+}
+
+// limitLength matches the first limit items it is given.
+type limitLength struct {
+	count int
+	limit int
+	mtx   sync.Mutex
+}
+
+func (c *limitLength) Match(raw interface{}) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.count++
+	return c.count <= c.limit
+}
+
 // NewInComparator returns a comparator which returns true if the operand matches any of the given values.
-// The comparator expects a list of strings under the key "Values" as part of the given argument.
+// The comparator expects a list of strings, numbers or booleans under the key "Values" as part of the given argument.
+// All items in the list must be of the same kind, the values are compared just as NewEqualComparator does.
 func NewInComparator(arg map[string]interface{}) (Comparator, error) {
 	raw, ok := arg["Values"]
 	if !ok {
@@ -58,154 +163,611 @@ func NewInComparator(arg map[string]interface{}) (Comparator, error) {
 	}
 	value := reflect.ValueOf(raw)
 	if value.Kind() != reflect.Slice {
-		return nil, errors.New("filter argument 'Values' should be a list of strings")
+		return nil, errors.New("filter argument 'Values' should be a list of strings, numbers or booleans")
 	}
 
-	values := make([]string, value.Len())
+	var category string
+	values := make([]interface{}, value.Len())
 	for i := 0; i < value.Len(); i++ {
-		rawItem := value.Index(i).Interface()
-		item, ok := rawItem.(string)
+		item, ok := coerceValue(value.Index(i).Interface())
 		if !ok {
-			return nil, errors.New("filter argument 'Values' should be a list of strings")
+			return nil, errors.New("filter argument 'Values' should be a list of strings, numbers or booleans")
+		}
+		if i == 0 {
+			category = valueCategory(item)
+		} else if valueCategory(item) != category {
+			return nil, errors.New("filter argument 'Values' should all be of the same kind")
 		}
 		values[i] = item
 	}
 
-	return inComparator{values: values}, nil
+	return inComparator{values: values, category: category}, nil
 }
 
-// NewIntegerComparator returns a NewListOperator for integer operations supported by integerComparator,
+// NewIntegerComparator returns a NewListOperator for integer operations supported by numberComparator,
 // specifically <, <=, > and >=.
 // The returned NewListOperation expects an int with the key "Value" as part of the given argument.
 func NewIntegerComparator(op string) NewListOperation {
+	checkNumberOperation(op)
+	return func(arg map[string]interface{}) (Comparator, error) {
+		raw, ok := arg["Value"]
+		if !ok {
+			return nil, errors.New("filter argument is missing 'Value'")
+		}
+		value, ok := raw.(int)
+		if !ok {
+			return nil, errors.New("filter argument 'Value' must be an integer")
+		}
+
+		return numberComparator{operation: op, value: int64(value)}, nil
+	}
+}
+
+// NewNumberComparator returns a NewListOperator for numeric operations, specifically <, <=, > and >=.
+// The returned NewListOperation expects an int or a float with the key "Value" as part of the given argument.
+// The comparison is numeric so fields of any integer or float type can be compared with either an int or float Value.
+func NewNumberComparator(op string) NewListOperation {
+	checkNumberOperation(op)
+	return func(arg map[string]interface{}) (Comparator, error) {
+		raw, ok := arg["Value"]
+		if !ok {
+			return nil, errors.New("filter argument is missing 'Value'")
+		}
+		value, ok := coerceValue(raw)
+		if !ok || valueCategory(value) != numberCategory {
+			return nil, errors.New("filter argument 'Value' must be an integer or float")
+		}
+
+		return numberComparator{operation: op, value: value}, nil
+	}
+}
+
+func checkNumberOperation(op string) {
 	switch op {
 	case ">", ">=", "<", "<=":
-		break
+		return
 	default:
 		panic(fmt.Sprintf("unsupported integer comparison operation %q", op))
 	}
+}
+
+var (
+	// MaxRegexpLength is the maximum length of a regular expression accepted by the MATCHES operation.
+	MaxRegexpLength = 256
+
+	// MaxRegexpInstructions is the maximum number of instructions in the compiled program of a regular expression
+	// accepted by the MATCHES operation. It limits the complexity of an expression, ie large repetition counts, and
+	// with that the work done matching each item of a list.
+	MaxRegexpInstructions = 1000
+)
+
+// NewStringComparator returns a NewListOperation for string operations, specifically ==, CONTAINS, STARTS WITH and
+// ENDS WITH. When ignoreCase is true the strings are compared without regard to case.
+// The returned NewListOperation expects a string with the key "Value" as part of the given argument.
+func NewStringComparator(op string, ignoreCase bool) NewListOperation {
+	switch op {
+	case stringEquals, stringContains, stringStartsWith, stringEndsWith:
+		break
+	default:
+		panic(fmt.Sprintf("unsupported string comparison operation %q", op))
+	}
 	return func(arg map[string]interface{}) (Comparator, error) {
 		raw, ok := arg["Value"]
 		if !ok {
 			return nil, errors.New("filter argument is missing 'Value'")
 		}
-		value, ok := raw.(int)
+		value, ok := raw.(string)
 		if !ok {
-			return nil, errors.New("filter argument 'Value' must be an integer")
+			return nil, errors.New("filter argument 'Value' must be a string")
+		}
+		if ignoreCase {
+			value = strings.ToLower(value)
 		}
 
-		return integerComparator{operation: op, value: value}, nil
+		return stringComparator{operation: op, value: value, ignoreCase: ignoreCase}, nil
 	}
 }
 
-// NewNotInComparator wraps NewInComparator returning the opposite boolean.
-func NewNotInComparator(arg map[string]interface{}) (Comparator, error) {
-	in, err := NewInComparator(arg)
+// NewMatchComparator returns a comparator which matches strings against the regular expression given with the key
+// "Value" in the argument. The syntax is that of the regexp package, the expression is unanchored so it can match any
+// part of the string and the flag (?i) makes it case-insensitive. The expression is compiled once for the comparator.
+// Expressions longer than MaxRegexpLength or more complex than MaxRegexpInstructions are rejected.
+func NewMatchComparator(arg map[string]interface{}) (Comparator, error) {
+	raw, ok := arg["Value"]
+	if !ok {
+		return nil, errors.New("filter argument is missing 'Value'")
+	}
+	expr, ok := raw.(string)
+	if !ok {
+		return nil, errors.New("filter argument 'Value' must be a regular expression string")
+	}
+	if len(expr) > MaxRegexpLength {
+		return nil, fmt.Errorf("filter argument 'Value' is longer than the maximum regular expression length %d", MaxRegexpLength)
+	}
+
+	parsed, err := syntax.Parse(expr, syntax.Perl)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("filter argument 'Value' is not a valid regular expression: %v", err)
 	}
-	return NotComparator{Child: in}, nil
+	prog, err := syntax.Compile(parsed.Simplify())
+	if err != nil {
+		return nil, fmt.Errorf("filter argument 'Value' is not a valid regular expression: %v", err)
+	}
+	if len(prog.Inst) > MaxRegexpInstructions {
+		return nil, errors.New("filter argument 'Value' regular expression is too complex")
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("filter argument 'Value' is not a valid regular expression: %v", err)
+	}
+	return regexpComparator{re: re}, nil
 }
 
-// NewLimitLengthComparator returns a comparator which simply limits the length of the results to the integer
-// given with the key "Value" in the argument.
-func NewLimitLengthComparator(arg map[string]interface{}) (Comparator, error) {
+// NewNullComparator returns a NewListOperation for null and existence checks, specifically IS NULL, IS NOT NULL and
+// EXISTS. No argument is needed for these operations.
+//
+// IS NULL matches fields which are nil, a nil pointer, an empty list or map or can't be found, which happens when a
+// level of the field path is missing or its parent is nil. IS NOT NULL matches all other fields. EXISTS matches any
+// field which can be found, even if its value is null.
+func NewNullComparator(op string) NewListOperation {
+	switch op {
+	case nullIs, nullIsNot, nullExist:
+		break
+	default:
+		panic(fmt.Sprintf("unsupported null comparison operation %q", op))
+	}
+	return func(arg map[string]interface{}) (Comparator, error) {
+		return nullComparator{operation: op}, nil
+	}
+}
+
+// NewQuantifierComparator returns a NewListOperation for quantified filters over a list field, specifically ANY, ALL
+// and NONE. The returned NewListOperation expects a filter object with the key "Filter" as part of the given argument,
+// it is defined just as the filter argument of ResolveListField and is applied to each item of the list field. An
+// empty Field in the nested filter refers to the item itself so lists of strings or numbers can also be filtered.
+//
+// ANY matches if the nested filter matches at least one item, ALL if it matches every item and NONE if it matches no
+// item. An empty or nil list matches ALL and NONE but not ANY.
+func NewQuantifierComparator(op string) NewListOperation {
+	switch op {
+	case quantifierAny, quantifierAll, quantifierNone:
+		break
+	default:
+		panic(fmt.Sprintf("unsupported quantifier operation %q", op))
+	}
+	return func(arg map[string]interface{}) (Comparator, error) {
+		raw, ok := arg["Filter"]
+		if !ok {
+			return nil, errors.New("filter argument is missing 'Filter'")
+		}
+		object, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("filter argument 'Filter' must be a filter object")
+		}
+		var lf listFilterJSON
+		if err := decodeVariable(object, &lf); err != nil {
+			return nil, fmt.Errorf("unable to parse filter argument 'Filter': %v", err)
+		}
+		lf.normalizeArguments()
+		filter, err := newListFilter(&lf)
+		if err != nil {
+			return nil, fmt.Errorf("filter argument 'Filter' is invalid: %v", err)
+		}
+		if len(filter.transforms) > 0 {
+			return nil, errors.New("filter argument 'Filter' can not include operations which work on the whole list")
+		}
+
+		return quantifierComparator{operation: op, filter: filter}, nil
+	}
+}
+
+// NewLengthComparator returns a comparator for the length of a list field.
+// The comparator expects an integer with the key "Value" and optionally one of the operations ==, !=, <, <=, > or >=
+// with the key "Operation" as part of the given argument, the default operation is ==. A nil list has a length of 0.
+func NewLengthComparator(arg map[string]interface{}) (Comparator, error) {
 	raw, ok := arg["Value"]
 	if !ok {
 		return nil, errors.New("filter argument is missing 'Value'")
 	}
-	limit, ok := raw.(int)
+	value, ok := raw.(int)
 	if !ok {
 		return nil, errors.New("filter argument 'Value' must be an integer")
 	}
+	op := "=="
+	if rawOp, ok := arg["Operation"]; ok {
+		op, ok = rawOp.(string)
+		if !ok {
+			return nil, errors.New("filter argument 'Operation' must be a string")
+		}
+	}
 
-	return &limitLength{limit: limit}, nil
+	var newOp NewListOperation
+	switch op {
+	case "==":
+		newOp = NewEqualComparator
+	case "!=":
+		newOp = NewNotEqualComparator
+	case ">", ">=", "<", "<=":
+		newOp = NewNumberComparator(op)
+	default:
+		return nil, fmt.Errorf("filter argument 'Operation' %q is not one of ==, !=, <, <=, > or >=", op)
+	}
+	length, err := newOp(map[string]interface{}{"Value": value})
+	if err != nil {
+		return nil, err
+	}
+
+	return lengthComparator{length: length}, nil
 }
 
+// Clock returns the current time. It is used by comparators with arguments relative to the current time so the time
+// can be controlled, for instance in tests.
+type Clock func() time.Time
+
+// NewTimeComparator returns a NewListOperation for time operations, specifically BEFORE, AFTER and BETWEEN.
+// Times in the argument are RFC 3339 strings, ie "2019-10-12T07:20:50Z", and the fields compared can be a time.Time,
+// *time.Time or a RFC 3339 string.
+//
+// BEFORE and AFTER expect a time with the key "Value" and match fields strictly before or after that time.
+// BETWEEN expects either the keys "From" and "To" and matches fields from and including From up to and including To
+// or a duration such as "24h" with the key "Within" which matches fields within that duration before the current
+// time given by the clock, giving both is an error. The clock is read once when the comparator is created so each item
+// in a list is compared with the same time.
+func NewTimeComparator(op string, clock Clock) NewListOperation {
+	switch op {
+	case timeBefore, timeAfter, timeBetween:
+		break
+	default:
+		panic(fmt.Sprintf("unsupported time comparison operation %q", op))
+	}
+	return func(arg map[string]interface{}) (Comparator, error) {
+		if op != timeBetween {
+			value, err := timeArgument(arg, "Value")
+			if err != nil {
+				return nil, err
+			}
+			return timeComparator{operation: op, value: value}, nil
+		}
+
+		if raw, ok := arg["Within"]; ok {
+			_, hasFrom := arg["From"]
+			_, hasTo := arg["To"]
+			if hasFrom || hasTo {
+				return nil, errors.New("filter argument 'Within' can't be combined with 'From' or 'To'")
+			}
+			within, ok := raw.(string)
+			if !ok {
+				return nil, errors.New("filter argument 'Within' must be a duration string such as \"24h\"")
+			}
+			duration, err := time.ParseDuration(within)
+			if err != nil {
+				return nil, fmt.Errorf("filter argument 'Within' is not a valid duration: %v", err)
+			}
+			if duration < 0 {
+				return nil, errors.New("filter argument 'Within' must not be a negative duration")
+			}
+			now := clock()
+			return timeComparator{operation: op, from: now.Add(-duration), to: now}, nil
+		}
+
+		from, err := timeArgument(arg, "From")
+		if err != nil {
+			return nil, err
+		}
+		to, err := timeArgument(arg, "To")
+		if err != nil {
+			return nil, err
+		}
+		if to.Before(from) {
+			return nil, errors.New("filter argument 'To' must not be before 'From'")
+		}
+		return timeComparator{operation: op, from: from, to: to}, nil
+	}
+}
+
+// timeArgument parses the RFC 3339 time found in the argument with the given key.
+func timeArgument(arg map[string]interface{}, key string) (time.Time, error) {
+	raw, ok := arg[key]
+	if !ok {
+		return time.Time{}, fmt.Errorf("filter argument is missing '%s'", key)
+	}
+	value, ok := raw.(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("filter argument '%s' must be a RFC 3339 time string", key)
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("filter argument '%s' is not a valid RFC 3339 time: %v", key, err)
+	}
+	return t, nil
+}
+
+// NewNotInComparator wraps NewInComparator returning the opposite boolean.
+func NewNotInComparator(arg map[string]interface{}) (Comparator, error) {
+	in, err := NewInComparator(arg)
+	if err != nil {
+		return nil, err
+	}
+	return NotComparator{Child: in}, nil
+}
+
+// equalComparator matches values equal to its value, it is a canonical value as returned by coerceValue.
+type equalComparator struct {
+	value interface{}
+}
+
+func (c equalComparator) Match(raw interface{}) bool {
+	in, ok := coerceValue(raw)
+	return ok && valuesEqual(in, c.value)
+}
+
+func (c equalComparator) CheckType(raw interface{}) error {
+	return checkCategory(raw, valueCategory(c.value))
+}
+
+// inComparator matches values equal to any of its values, these are canonical values as returned by coerceValue and
+// all of the given category.
 type inComparator struct {
-	values []string
+	values   []interface{}
+	category string
 }
 
 func (c inComparator) Match(raw interface{}) bool {
-	in, ok := raw.(string)
+	in, ok := coerceValue(raw)
 	if !ok {
 		return false
 	}
 	for _, v := range c.values {
-		if in == v {
+		if valuesEqual(in, v) {
 			return true
 		}
 	}
 	return false
 }
 
-type intEqual struct {
-	value int
+func (c inComparator) CheckType(raw interface{}) error {
+	if c.category == "" { // An empty list of values has no type
+		return nil
+	}
+	return checkCategory(raw, c.category)
+}
+
+// numberComparator supports numeric comparisons for these operations <, <=, > and >=.
+// The value is a canonical number as returned by coerceValue, fields of any integer or float type are compared
+// numerically so a float is not truncated when compared with an int. A NaN field never matches.
+// It is not a TypeChecker, a field which isn't a number never matches rather than being an error, only the equality
+// and IN comparators check the type of fields.
+type numberComparator struct {
+	operation string
+	value     interface{}
 }
 
-func (c intEqual) Match(raw interface{}) bool {
-	in, ok := raw.(int)
+func (c numberComparator) Match(raw interface{}) bool {
+	in, ok := coerceValue(raw)
+	if !ok {
+		return false
+	}
+	result, ok := compareNumbers(in, c.value)
 	if !ok {
 		return false
 	}
-	return in == c.value
+	switch c.operation {
+	case ">":
+		return result > 0
+	case ">=":
+		return result >= 0
+	case "<":
+		return result < 0
+	case "<=":
+		return result <= 0
+	default:
+		panic("unknown operation, this struct should always be initialized with NewNumberComparator making this unreachable")
+	}
 }
 
-// integerComparator supports comparisons between ints for a these operations <, <=, > and >=.
-type integerComparator struct {
+// timeComparator supports time comparisons for these operations BEFORE, AFTER and BETWEEN.
+// BEFORE and AFTER compare with value, BETWEEN with from and to.
+type timeComparator struct {
 	operation string
-	value     int
+	value     time.Time
+	from      time.Time
+	to        time.Time
 }
 
-func (c integerComparator) Match(raw interface{}) bool {
-	in, ok := raw.(int)
-	if !ok {
-		rawfloat, ok := raw.(float64)
-		if !ok {
+func (c timeComparator) Match(raw interface{}) bool {
+	var in time.Time
+	switch t := raw.(type) {
+	case time.Time:
+		in = t
+	case *time.Time:
+		if t == nil {
+			return false
+		}
+		in = *t
+	case string:
+		var err error
+		in, err = time.Parse(time.RFC3339, t)
+		if err != nil {
 			return false
 		}
-		in = int(rawfloat)
-		// This covers the case where the underlying field is a float but the comparison in the filter in an int
-		// this can happen because the distinction between float and int is not strong in the JSON
+	default:
+		return false
+	}
+
+	switch c.operation {
+	case timeBefore:
+		return in.Before(c.value)
+	case timeAfter:
+		return in.After(c.value)
+	case timeBetween:
+		return !in.Before(c.from) && !in.After(c.to)
+	default:
+		panic("unknown operation, this struct should always be initialized with NewTimeComparator making this unreachable")
 	}
+}
+
+// nullComparator supports the null and existence checks IS NULL, IS NOT NULL and EXISTS.
+type nullComparator struct {
+	operation string
+}
+
+// Match checks a value assuming its field exists.
+func (c nullComparator) Match(raw interface{}) bool {
+	return c.MatchPresence(raw, true)
+}
+
+func (c nullComparator) MatchPresence(raw interface{}, exists bool) bool {
 	switch c.operation {
-	case ">":
-		return in > c.value
-	case ">=":
-		return in >= c.value
-	case "<":
-		return in < c.value
-	case "<=":
-		return in <= c.value
+	case nullIs:
+		return !exists || isNullValue(raw)
+	case nullIsNot:
+		return exists && !isNullValue(raw)
+	case nullExist:
+		return exists
 	default:
-		panic("unknown operation, this struct should always be initialized with NewIntegerComparator making this unreachable")
+		panic("unknown operation, this struct should always be initialized with NewNullComparator making this unreachable")
 	}
 }
 
-type limitLength struct {
-	limit int
-	count int
-	mtx   sync.Mutex
+// isNullValue returns true if the value is nil, a nil pointer or an empty slice or map.
+func isNullValue(raw interface{}) bool {
+	if isNil(raw) {
+		return true
+	}
+	value := reflect.ValueOf(raw)
+	switch value.Kind() {
+	case reflect.Slice, reflect.Map:
+		return value.Len() == 0
+	}
+	return false
 }
 
-func (c *limitLength) Match(raw interface{}) bool {
-	c.mtx.Lock()
-	defer c.mtx.Unlock()
-	c.count++
-	if c.count > c.limit {
+// quantifierComparator applies its filter to each item of a list for these operations ANY, ALL and NONE.
+type quantifierComparator struct {
+	operation string
+	filter    *listFilter
+}
+
+func (c quantifierComparator) Match(raw interface{}) bool {
+	list, ok := listValue(raw)
+	if !ok {
 		return false
 	}
-	return true
+	for i := 0; i < list.Len(); i++ {
+		matched, err := c.filter.match(list.Index(i).Interface())
+		if err != nil {
+			return false
+		}
+		switch c.operation {
+		case quantifierAny:
+			if matched {
+				return true
+			}
+		case quantifierAll:
+			if !matched {
+				return false
+			}
+		case quantifierNone:
+			if matched {
+				return false
+			}
+		default:
+			panic("unknown operation, this struct should always be initialized with NewQuantifierComparator making this unreachable")
+		}
+	}
+	return c.operation != quantifierAny
 }
 
-type stringEqual struct {
-	value string
+// CheckType implements TypeChecker by checking the value is a list and the fields of each item with the filter.
+func (c quantifierComparator) CheckType(raw interface{}) error {
+	list, ok := listValue(raw)
+	if !ok {
+		return fmt.Errorf("a value of type %T is not a list", raw)
+	}
+	for i := 0; i < list.Len(); i++ {
+		if _, err := c.filter.fieldValues(list.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lengthComparator matches the length of a list with its length comparator.
+type lengthComparator struct {
+	length Comparator
+}
+
+func (c lengthComparator) Match(raw interface{}) bool {
+	list, ok := listValue(raw)
+	if !ok {
+		return false
+	}
+	return c.length.Match(list.Len())
+}
+
+func (c lengthComparator) CheckType(raw interface{}) error {
+	if _, ok := listValue(raw); !ok {
+		return fmt.Errorf("a value of type %T is not a list", raw)
+	}
+	return nil
+}
+
+// listValue returns the reflect.Value of a slice or array, false is returned if the value is neither.
+// A nil value is returned as an empty list.
+func listValue(raw interface{}) (reflect.Value, bool) {
+	if raw == nil {
+		return reflect.ValueOf([]interface{}{}), true
+	}
+	value := reflect.ValueOf(raw)
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		return value, true
+	}
+	return value, false
 }
 
-func (c stringEqual) Match(raw interface{}) bool {
+type regexpComparator struct {
+	re *regexp.Regexp
+}
+
+func (c regexpComparator) Match(raw interface{}) bool {
 	in, ok := raw.(string)
 	if !ok {
 		return false
 	}
-	return in == c.value
+	return c.re.MatchString(in)
+}
+
+// stringComparator supports comparisons between strings for these operations ==, CONTAINS, STARTS WITH and
+// ENDS WITH. When ignoreCase is set the value is expected to already be lowercase.
+type stringComparator struct {
+	operation  string
+	value      string
+	ignoreCase bool
+}
+
+func (c stringComparator) Match(raw interface{}) bool {
+	in, ok := raw.(string)
+	if !ok {
+		return false
+	}
+	if c.ignoreCase {
+		if c.operation == stringEquals {
+			return strings.EqualFold(in, c.value)
+		}
+		in = strings.ToLower(in)
+	}
+	switch c.operation {
+	case stringEquals:
+		return in == c.value
+	case stringContains:
+		return strings.Contains(in, c.value)
+	case stringStartsWith:
+		return strings.HasPrefix(in, c.value)
+	case stringEndsWith:
+		return strings.HasSuffix(in, c.value)
+	default:
+		panic("unknown operation, this struct should always be initialized with NewStringComparator making this unreachable")
+	}
 }
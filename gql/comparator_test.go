@@ -1,8 +1,87 @@
 package gql
 
-import "testing"
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testKind string
+
+type testPriority uint8
+
+type testFlag bool
+
+func TestBooleanComparators(t *testing.T) {
+	a := equalComparator{value: "a"}
+	b := equalComparator{value: "b"}
+
+	tests := []struct {
+		description string
+		comparator  Comparator
+		operand     interface{}
+		want        bool
+	}{
+		{
+			description: "And all match",
+			comparator:  AndComparator{Children: []Comparator{a, a}},
+			operand:     "a",
+			want:        true,
+		},
+		{
+			description: "And one does not match",
+			comparator:  AndComparator{Children: []Comparator{a, b}},
+			operand:     "a",
+			want:        false,
+		},
+		{
+			description: "And no children",
+			comparator:  AndComparator{},
+			operand:     "a",
+			want:        true,
+		},
+		{
+			description: "Or one matches",
+			comparator:  OrComparator{Children: []Comparator{b, a}},
+			operand:     "a",
+			want:        true,
+		},
+		{
+			description: "Or none match",
+			comparator:  OrComparator{Children: []Comparator{b, b}},
+			operand:     "a",
+			want:        false,
+		},
+		{
+			description: "Or no children",
+			comparator:  OrComparator{},
+			operand:     "a",
+			want:        false,
+		},
+		{
+			description: "Not of Or",
+			comparator:  NotComparator{Child: OrComparator{Children: []Comparator{a, b}}},
+			operand:     "c",
+			want:        true,
+		},
+	}
+
+	for _, test := range tests {
+		if got := test.comparator.Match(test.operand); got != test.want {
+			t.Errorf("Test %q - got %t, want %t", test.description, got, test.want)
+		}
+	}
+}
 
 func TestOperators(t *testing.T) {
+	now := time.Date(2019, 10, 12, 7, 20, 50, 0, time.UTC)
+	clock := func() time.Time { return now }
+	hourAgo := now.Add(-time.Hour)
+	dayAgo := now.Add(-24 * time.Hour)
+	var nilTime *time.Time
+
 	tests := []struct {
 		description          string
 		operation            NewListOperation
@@ -52,11 +131,192 @@ func TestOperators(t *testing.T) {
 			wantInvalidOperation: true,
 		},
 		{
-			description:          "NewEqualComparator invalid argument, float",
+			description:          "NewEqualComparator invalid argument, object",
+			operation:            NewEqualComparator,
+			arguments:            map[string]interface{}{"Value": map[string]interface{}{"a": 1}},
+			wantInvalidOperation: true,
+		},
+		{
+			description:          "NewEqualComparator invalid argument, list",
 			operation:            NewEqualComparator,
-			arguments:            map[string]interface{}{"Value": 3.14},
+			arguments:            map[string]interface{}{"Value": []interface{}{1}},
 			wantInvalidOperation: true,
 		},
+		{
+			description: "bool equal valid and true",
+			operation:   NewEqualComparator,
+			arguments:   map[string]interface{}{"Value": true},
+			operand:     true,
+			want:        true,
+		},
+		{
+			description: "bool equal valid and false",
+			operation:   NewEqualComparator,
+			arguments:   map[string]interface{}{"Value": false},
+			operand:     true,
+			want:        false,
+		},
+		{
+			description: "bool equal, named bool operand",
+			operation:   NewEqualComparator,
+			arguments:   map[string]interface{}{"Value": true},
+			operand:     testFlag(true),
+			want:        true,
+		},
+		{
+			description: "bool equal, string operand",
+			operation:   NewEqualComparator,
+			arguments:   map[string]interface{}{"Value": true},
+			operand:     "true",
+			want:        false,
+		},
+		{
+			description: "int equal, int64 operand",
+			operation:   NewEqualComparator,
+			arguments:   map[string]interface{}{"Value": 55},
+			operand:     int64(55),
+			want:        true,
+		},
+		{
+			description: "int equal, int32 operand",
+			operation:   NewEqualComparator,
+			arguments:   map[string]interface{}{"Value": -5},
+			operand:     int32(-5),
+			want:        true,
+		},
+		{
+			description: "int equal, uint operand",
+			operation:   NewEqualComparator,
+			arguments:   map[string]interface{}{"Value": 5},
+			operand:     uint(5),
+			want:        true,
+		},
+		{
+			description: "int equal, uint8 operand",
+			operation:   NewEqualComparator,
+			arguments:   map[string]interface{}{"Value": 5},
+			operand:     uint8(5),
+			want:        true,
+		},
+		{
+			description: "int equal, negative value and max uint64 operand",
+			operation:   NewEqualComparator,
+			arguments:   map[string]interface{}{"Value": -1},
+			operand:     uint64(math.MaxUint64),
+			want:        false,
+		},
+		{
+			description: "int equal, float32 operand",
+			operation:   NewEqualComparator,
+			arguments:   map[string]interface{}{"Value": 2},
+			operand:     float32(2),
+			want:        true,
+		},
+		{
+			description: "int equal, pointer operand",
+			operation:   NewEqualComparator,
+			arguments:   map[string]interface{}{"Value": 2},
+			operand:     &[]int{2}[0],
+			want:        true,
+		},
+		{
+			description: "int equal, named int operand",
+			operation:   NewEqualComparator,
+			arguments:   map[string]interface{}{"Value": 2},
+			operand:     testPriority(2),
+			want:        true,
+		},
+		{
+			description: "string equal, named string operand",
+			operation:   NewEqualComparator,
+			arguments:   map[string]interface{}{"Value": "story"},
+			operand:     testKind("story"),
+			want:        true,
+		},
+		{
+			description: "string not equal, named string operand",
+			operation:   NewNotEqualComparator,
+			arguments:   map[string]interface{}{"Value": "story"},
+			operand:     testKind("video"),
+			want:        true,
+		},
+		{
+			description: "float equal valid and true",
+			operation:   NewEqualComparator,
+			arguments:   map[string]interface{}{"Value": 3.14},
+			operand:     3.14,
+			want:        true,
+		},
+		{
+			description: "float equal valid and false",
+			operation:   NewEqualComparator,
+			arguments:   map[string]interface{}{"Value": 3.14},
+			operand:     3.15,
+			want:        false,
+		},
+		{
+			description: "float equal, negative zero equals zero",
+			operation:   NewEqualComparator,
+			arguments:   map[string]interface{}{"Value": 0.0},
+			operand:     math.Copysign(0, -1),
+			want:        true,
+		},
+		{
+			description: "float equal, NaN operand never matches",
+			operation:   NewEqualComparator,
+			arguments:   map[string]interface{}{"Value": 3.14},
+			operand:     math.NaN(),
+			want:        false,
+		},
+		{
+			description: "float equal, int operand compared numerically",
+			operation:   NewEqualComparator,
+			arguments:   map[string]interface{}{"Value": 3.0},
+			operand:     3,
+			want:        true,
+		},
+		{
+			description: "float equal, int operand not equal",
+			operation:   NewEqualComparator,
+			arguments:   map[string]interface{}{"Value": 3.5},
+			operand:     3,
+			want:        false,
+		},
+		{
+			description: "float equal invalid, mismatched operand",
+			operation:   NewEqualComparator,
+			arguments:   map[string]interface{}{"Value": 3.14},
+			operand:     "3.14",
+			want:        false,
+		},
+		{
+			description: "int equal, float operand compared numerically",
+			operation:   NewEqualComparator,
+			arguments:   map[string]interface{}{"Value": 3},
+			operand:     3.0,
+			want:        true,
+		},
+		{
+			description: "int equal, float operand is not truncated",
+			operation:   NewEqualComparator,
+			arguments:   map[string]interface{}{"Value": 3},
+			operand:     3.9,
+			want:        false,
+		},
+		{
+			description: "float not equal, NaN operand",
+			operation:   NewNotEqualComparator,
+			arguments:   map[string]interface{}{"Value": 3.14},
+			operand:     math.NaN(),
+			want:        true,
+		},
+		{
+			description: "float not equal valid and false",
+			operation:   NewNotEqualComparator,
+			arguments:   map[string]interface{}{"Value": 3.14},
+			operand:     3.14,
+			want:        false,
+		},
 		{
 			description: "int equal valid and true",
 			operation:   NewEqualComparator,
@@ -126,30 +386,6 @@ func TestOperators(t *testing.T) {
 			arguments:            map[string]interface{}{"nothing": "a"},
 			wantInvalidOperation: true,
 		},
-		{
-			description: "limitLength to 0", // Note limitLength is better tested as part of TestResolveListField
-			operation:   NewLimitLengthComparator,
-			arguments:   map[string]interface{}{"Value": 0},
-			want:        false,
-		},
-		{
-			description: "limitLength > 0",
-			operation:   NewLimitLengthComparator,
-			arguments:   map[string]interface{}{"Value": 1},
-			want:        true,
-		},
-		{
-			description:          "NewLimitLengthComparator invalid argument, missing value",
-			operation:            NewLimitLengthComparator,
-			arguments:            map[string]interface{}{"nothing": "a"},
-			wantInvalidOperation: true,
-		},
-		{
-			description:          "NewLimitLengthComparator invalid argument, string value",
-			operation:            NewLimitLengthComparator,
-			arguments:            map[string]interface{}{"Value": "a"},
-			wantInvalidOperation: true,
-		},
 		{
 			description: "IN expect true",
 			operation:   NewInComparator,
@@ -197,6 +433,68 @@ func TestOperators(t *testing.T) {
 			arguments:            map[string]interface{}{"Values": "a, b, c"},
 			wantInvalidOperation: true,
 		},
+		{
+			description: "IN integers expect true",
+			operation:   NewInComparator,
+			arguments:   map[string]interface{}{"Values": []interface{}{1, 2, 3}},
+			operand:     2,
+			want:        true,
+		},
+		{
+			description: "IN integers, int64 operand",
+			operation:   NewInComparator,
+			arguments:   map[string]interface{}{"Values": []int{1, 2, 3}},
+			operand:     int64(3),
+			want:        true,
+		},
+		{
+			description: "IN integers expect false",
+			operation:   NewInComparator,
+			arguments:   map[string]interface{}{"Values": []interface{}{1, 2, 3}},
+			operand:     uint16(4),
+			want:        false,
+		},
+		{
+			description: "IN mixed numbers, float operand",
+			operation:   NewInComparator,
+			arguments:   map[string]interface{}{"Values": []interface{}{1, 2.5}},
+			operand:     2.5,
+			want:        true,
+		},
+		{
+			description: "IN booleans",
+			operation:   NewInComparator,
+			arguments:   map[string]interface{}{"Values": []interface{}{true}},
+			operand:     true,
+			want:        true,
+		},
+		{
+			description: "IN strings, named string operand",
+			operation:   NewInComparator,
+			arguments:   map[string]interface{}{"Values": []interface{}{"story", "video"}},
+			operand:     testKind("video"),
+			want:        true,
+		},
+		{
+			description: "IN empty list",
+			operation:   NewInComparator,
+			arguments:   map[string]interface{}{"Values": []interface{}{}},
+			operand:     "a",
+			want:        false,
+		},
+		{
+			description: "NOT IN integers expect true",
+			operation:   NewNotInComparator,
+			arguments:   map[string]interface{}{"Values": []interface{}{1, 2, 3}},
+			operand:     int8(4),
+			want:        true,
+		},
+		{
+			description:          "IN invalid argument, object in list",
+			operation:            NewInComparator,
+			arguments:            map[string]interface{}{"Values": []interface{}{map[string]interface{}{}}},
+			wantInvalidOperation: true,
+		},
 		{
 			description:          "NOT IN invalid argument, integer in list",
 			operation:            NewNotInComparator,
@@ -273,29 +571,960 @@ func TestOperators(t *testing.T) {
 			wantInvalidOperation: true,
 		},
 		{
-			description:          "Integrer Comparator invalid argument, string value",
+			description: "Integer Comparator, float operand is not truncated",
+			operation:   NewIntegerComparator(">"),
+			arguments:   map[string]interface{}{"Value": 4},
+			operand:     4.9,
+			want:        true,
+		},
+		{
+			description: "Integer Comparator, float operand is not truncated for <=",
+			operation:   NewIntegerComparator("<="),
+			arguments:   map[string]interface{}{"Value": 4},
+			operand:     4.1,
+			want:        false,
+		},
+		{
+			description:          "Integrer Comparator invalid argument, float value",
 			operation:            NewIntegerComparator(">"),
-			arguments:            map[string]interface{}{"Value": "five"},
+			arguments:            map[string]interface{}{"Value": 4.5},
 			wantInvalidOperation: true,
 		},
-	}
-
-	for _, test := range tests {
-		op, err := test.operation(test.arguments)
-		switch {
-		case err != nil && test.wantInvalidOperation:
-			continue
-		case err == nil && test.wantInvalidOperation:
-			t.Errorf("Test %q - got nil error want error on invalid operation", test.description)
-			continue
-		case err != nil && !test.wantInvalidOperation:
-			t.Errorf("Test %q - got invalid operation error want nil: %v", test.description, err)
-			continue
-		}
-
-		got := op.Match(test.operand)
-		if got != test.want {
-			t.Errorf("Test %q - got %t, want %t", test.description, got, test.want)
-		}
+		{
+			description: "Number Comparator, int value and int operand",
+			operation:   NewNumberComparator("<"),
+			arguments:   map[string]interface{}{"Value": 5},
+			operand:     4,
+			want:        true,
+		},
+		{
+			description: "Number Comparator, int value and float operand",
+			operation:   NewNumberComparator(">"),
+			arguments:   map[string]interface{}{"Value": 4},
+			operand:     4.000001,
+			want:        true,
+		},
+		{
+			description: "Number Comparator, float value and float operand >",
+			operation:   NewNumberComparator(">"),
+			arguments:   map[string]interface{}{"Value": 4.5},
+			operand:     4.6,
+			want:        true,
+		},
+		{
+			description: "Number Comparator, float value and float operand > equal",
+			operation:   NewNumberComparator(">"),
+			arguments:   map[string]interface{}{"Value": 4.5},
+			operand:     4.5,
+			want:        false,
+		},
+		{
+			description: "Number Comparator, float value and float operand >= equal",
+			operation:   NewNumberComparator(">="),
+			arguments:   map[string]interface{}{"Value": 4.5},
+			operand:     4.5,
+			want:        true,
+		},
+		{
+			description: "Number Comparator, float value and float operand <",
+			operation:   NewNumberComparator("<"),
+			arguments:   map[string]interface{}{"Value": 4.5},
+			operand:     4.4,
+			want:        true,
+		},
+		{
+			description: "Number Comparator, float value and float operand <=",
+			operation:   NewNumberComparator("<="),
+			arguments:   map[string]interface{}{"Value": 4.5},
+			operand:     4.51,
+			want:        false,
+		},
+		{
+			description: "Number Comparator, float value and int operand",
+			operation:   NewNumberComparator(">"),
+			arguments:   map[string]interface{}{"Value": 4.5},
+			operand:     5,
+			want:        true,
+		},
+		{
+			description: "Number Comparator, float value and int operand false",
+			operation:   NewNumberComparator(">"),
+			arguments:   map[string]interface{}{"Value": 4.5},
+			operand:     4,
+			want:        false,
+		},
+		{
+			description: "Number Comparator, negative float value",
+			operation:   NewNumberComparator("<"),
+			arguments:   map[string]interface{}{"Value": -0.5},
+			operand:     -1,
+			want:        true,
+		},
+		{
+			description: "Number Comparator, infinite operand",
+			operation:   NewNumberComparator(">"),
+			arguments:   map[string]interface{}{"Value": 1e300},
+			operand:     math.Inf(1),
+			want:        true,
+		},
+		{
+			description: "Number Comparator, NaN operand never matches <",
+			operation:   NewNumberComparator("<"),
+			arguments:   map[string]interface{}{"Value": 4.5},
+			operand:     math.NaN(),
+			want:        false,
+		},
+		{
+			description: "Number Comparator, NaN operand never matches >=",
+			operation:   NewNumberComparator(">="),
+			arguments:   map[string]interface{}{"Value": 4},
+			operand:     math.NaN(),
+			want:        false,
+		},
+		{
+			description: "Number Comparator, string operand",
+			operation:   NewNumberComparator(">="),
+			arguments:   map[string]interface{}{"Value": 4.5},
+			operand:     "a",
+			want:        false,
+		},
+		{
+			description: "Number Comparator, int64 operand",
+			operation:   NewNumberComparator(">"),
+			arguments:   map[string]interface{}{"Value": 4},
+			operand:     int64(5),
+			want:        true,
+		},
+		{
+			description: "Number Comparator, large uint64 operand",
+			operation:   NewNumberComparator(">"),
+			arguments:   map[string]interface{}{"Value": 4},
+			operand:     uint64(math.MaxUint64),
+			want:        true,
+		},
+		{
+			description: "Number Comparator, float32 operand",
+			operation:   NewNumberComparator("<"),
+			arguments:   map[string]interface{}{"Value": 4.5},
+			operand:     float32(4.25),
+			want:        true,
+		},
+		{
+			description: "Number Comparator, named int operand",
+			operation:   NewNumberComparator("<="),
+			arguments:   map[string]interface{}{"Value": 2},
+			operand:     testPriority(2),
+			want:        true,
+		},
+		{
+			description:          "Number Comparator invalid argument, bool value",
+			operation:            NewNumberComparator(">"),
+			arguments:            map[string]interface{}{"Value": true},
+			wantInvalidOperation: true,
+		},
+		{
+			description:          "Number Comparator invalid argument, missing value",
+			operation:            NewNumberComparator(">"),
+			arguments:            map[string]interface{}{"nothing": "a"},
+			wantInvalidOperation: true,
+		},
+		{
+			description:          "Number Comparator invalid argument, string value",
+			operation:            NewNumberComparator(">"),
+			arguments:            map[string]interface{}{"Value": "4.5"},
+			wantInvalidOperation: true,
+		},
+		{
+			description: "CONTAINS expect true",
+			operation:   NewStringComparator("CONTAINS", false),
+			arguments:   map[string]interface{}{"Value": "ell"},
+			operand:     "hello",
+			want:        true,
+		},
+		{
+			description: "CONTAINS different case expect false",
+			operation:   NewStringComparator("CONTAINS", false),
+			arguments:   map[string]interface{}{"Value": "ELL"},
+			operand:     "hello",
+			want:        false,
+		},
+		{
+			description: "CONTAINS empty value expect true",
+			operation:   NewStringComparator("CONTAINS", false),
+			arguments:   map[string]interface{}{"Value": ""},
+			operand:     "hello",
+			want:        true,
+		},
+		{
+			description: "CONTAINS non-string operand",
+			operation:   NewStringComparator("CONTAINS", false),
+			arguments:   map[string]interface{}{"Value": "1"},
+			operand:     1,
+			want:        false,
+		},
+		{
+			description:          "CONTAINS invalid argument, missing value",
+			operation:            NewStringComparator("CONTAINS", false),
+			arguments:            map[string]interface{}{"nothing": "a"},
+			wantInvalidOperation: true,
+		},
+		{
+			description:          "CONTAINS invalid argument, int value",
+			operation:            NewStringComparator("CONTAINS", false),
+			arguments:            map[string]interface{}{"Value": 1},
+			wantInvalidOperation: true,
+		},
+		{
+			description: "STARTS WITH expect true",
+			operation:   NewStringComparator("STARTS WITH", false),
+			arguments:   map[string]interface{}{"Value": "he"},
+			operand:     "hello",
+			want:        true,
+		},
+		{
+			description: "STARTS WITH expect false",
+			operation:   NewStringComparator("STARTS WITH", false),
+			arguments:   map[string]interface{}{"Value": "lo"},
+			operand:     "hello",
+			want:        false,
+		},
+		{
+			description: "ENDS WITH expect true",
+			operation:   NewStringComparator("ENDS WITH", false),
+			arguments:   map[string]interface{}{"Value": "lo"},
+			operand:     "hello",
+			want:        true,
+		},
+		{
+			description: "ENDS WITH expect false",
+			operation:   NewStringComparator("ENDS WITH", false),
+			arguments:   map[string]interface{}{"Value": "he"},
+			operand:     "hello",
+			want:        false,
+		},
+		{
+			description: "EQUALS IGNORE CASE expect true",
+			operation:   NewStringComparator("==", true),
+			arguments:   map[string]interface{}{"Value": "HeLLo"},
+			operand:     "hEllO",
+			want:        true,
+		},
+		{
+			description: "EQUALS IGNORE CASE non-ASCII expect true",
+			operation:   NewStringComparator("==", true),
+			arguments:   map[string]interface{}{"Value": "ÉTÉ"},
+			operand:     "été",
+			want:        true,
+		},
+		{
+			description: "EQUALS IGNORE CASE expect false",
+			operation:   NewStringComparator("==", true),
+			arguments:   map[string]interface{}{"Value": "hello"},
+			operand:     "hello!",
+			want:        false,
+		},
+		{
+			description: "CONTAINS IGNORE CASE expect true",
+			operation:   NewStringComparator("CONTAINS", true),
+			arguments:   map[string]interface{}{"Value": "ELL"},
+			operand:     "hello",
+			want:        true,
+		},
+		{
+			description: "STARTS WITH IGNORE CASE expect true",
+			operation:   NewStringComparator("STARTS WITH", true),
+			arguments:   map[string]interface{}{"Value": "hE"},
+			operand:     "Hello",
+			want:        true,
+		},
+		{
+			description: "ENDS WITH IGNORE CASE expect true",
+			operation:   NewStringComparator("ENDS WITH", true),
+			arguments:   map[string]interface{}{"Value": "LO"},
+			operand:     "hello",
+			want:        true,
+		},
+		{
+			description: "ENDS WITH IGNORE CASE expect false",
+			operation:   NewStringComparator("ENDS WITH", true),
+			arguments:   map[string]interface{}{"Value": "HE"},
+			operand:     "hello",
+			want:        false,
+		},
+		{
+			description: "MATCHES expect true",
+			operation:   NewMatchComparator,
+			arguments:   map[string]interface{}{"Value": "^h.l+o$"},
+			operand:     "hello",
+			want:        true,
+		},
+		{
+			description: "MATCHES is unanchored",
+			operation:   NewMatchComparator,
+			arguments:   map[string]interface{}{"Value": "l+"},
+			operand:     "hello",
+			want:        true,
+		},
+		{
+			description: "MATCHES expect false",
+			operation:   NewMatchComparator,
+			arguments:   map[string]interface{}{"Value": "^[0-9]+$"},
+			operand:     "hello",
+			want:        false,
+		},
+		{
+			description: "MATCHES case-insensitive flag",
+			operation:   NewMatchComparator,
+			arguments:   map[string]interface{}{"Value": "(?i)^HELLO$"},
+			operand:     "hello",
+			want:        true,
+		},
+		{
+			description: "MATCHES non-string operand",
+			operation:   NewMatchComparator,
+			arguments:   map[string]interface{}{"Value": "1"},
+			operand:     1,
+			want:        false,
+		},
+		{
+			description:          "MATCHES invalid argument, missing value",
+			operation:            NewMatchComparator,
+			arguments:            map[string]interface{}{"nothing": "a"},
+			wantInvalidOperation: true,
+		},
+		{
+			description:          "MATCHES invalid argument, not a string",
+			operation:            NewMatchComparator,
+			arguments:            map[string]interface{}{"Value": 1},
+			wantInvalidOperation: true,
+		},
+		{
+			description:          "MATCHES invalid argument, invalid expression",
+			operation:            NewMatchComparator,
+			arguments:            map[string]interface{}{"Value": "(a"},
+			wantInvalidOperation: true,
+		},
+		{
+			description:          "MATCHES invalid argument, too long",
+			operation:            NewMatchComparator,
+			arguments:            map[string]interface{}{"Value": strings.Repeat("a", MaxRegexpLength+1)},
+			wantInvalidOperation: true,
+		},
+		{
+			description:          "MATCHES invalid argument, too complex",
+			operation:            NewMatchComparator,
+			arguments:            map[string]interface{}{"Value": "[a-z]{999}[0-9]{999}"},
+			wantInvalidOperation: true,
+		},
+		{
+			description: "BEFORE expect true",
+			operation:   NewTimeComparator("BEFORE", clock),
+			arguments:   map[string]interface{}{"Value": "2019-10-12T07:20:50Z"},
+			operand:     hourAgo,
+			want:        true,
+		},
+		{
+			description: "BEFORE equal time expect false",
+			operation:   NewTimeComparator("BEFORE", clock),
+			arguments:   map[string]interface{}{"Value": "2019-10-12T07:20:50Z"},
+			operand:     now,
+			want:        false,
+		},
+		{
+			description: "BEFORE with time zone offset",
+			operation:   NewTimeComparator("BEFORE", clock),
+			arguments:   map[string]interface{}{"Value": "2019-10-12T03:20:51-04:00"},
+			operand:     now,
+			want:        true,
+		},
+		{
+			description: "BEFORE pointer operand",
+			operation:   NewTimeComparator("BEFORE", clock),
+			arguments:   map[string]interface{}{"Value": "2019-10-12T07:20:50Z"},
+			operand:     &hourAgo,
+			want:        true,
+		},
+		{
+			description: "BEFORE nil pointer operand",
+			operation:   NewTimeComparator("BEFORE", clock),
+			arguments:   map[string]interface{}{"Value": "2019-10-12T07:20:50Z"},
+			operand:     nilTime,
+			want:        false,
+		},
+		{
+			description: "BEFORE string operand",
+			operation:   NewTimeComparator("BEFORE", clock),
+			arguments:   map[string]interface{}{"Value": "2019-10-12T07:20:50Z"},
+			operand:     "2019-10-12T07:20:49.5Z",
+			want:        true,
+		},
+		{
+			description: "BEFORE invalid string operand",
+			operation:   NewTimeComparator("BEFORE", clock),
+			arguments:   map[string]interface{}{"Value": "2019-10-12T07:20:50Z"},
+			operand:     "yesterday",
+			want:        false,
+		},
+		{
+			description: "BEFORE int operand",
+			operation:   NewTimeComparator("BEFORE", clock),
+			arguments:   map[string]interface{}{"Value": "2019-10-12T07:20:50Z"},
+			operand:     1,
+			want:        false,
+		},
+		{
+			description:          "BEFORE invalid argument, missing value",
+			operation:            NewTimeComparator("BEFORE", clock),
+			arguments:            map[string]interface{}{"nothing": "a"},
+			wantInvalidOperation: true,
+		},
+		{
+			description:          "BEFORE invalid argument, not RFC 3339",
+			operation:            NewTimeComparator("BEFORE", clock),
+			arguments:            map[string]interface{}{"Value": "2019-10-12"},
+			wantInvalidOperation: true,
+		},
+		{
+			description:          "BEFORE invalid argument, int value",
+			operation:            NewTimeComparator("BEFORE", clock),
+			arguments:            map[string]interface{}{"Value": 1570864850},
+			wantInvalidOperation: true,
+		},
+		{
+			description: "AFTER expect true",
+			operation:   NewTimeComparator("AFTER", clock),
+			arguments:   map[string]interface{}{"Value": "2019-10-11T07:20:50Z"},
+			operand:     hourAgo,
+			want:        true,
+		},
+		{
+			description: "AFTER equal time expect false",
+			operation:   NewTimeComparator("AFTER", clock),
+			arguments:   map[string]interface{}{"Value": "2019-10-11T07:20:50Z"},
+			operand:     dayAgo,
+			want:        false,
+		},
+		{
+			description: "AFTER zero time argument",
+			operation:   NewTimeComparator("AFTER", clock),
+			arguments:   map[string]interface{}{"Value": "0001-01-01T00:00:00Z"},
+			operand:     dayAgo,
+			want:        true,
+		},
+		{
+			description: "BETWEEN expect true",
+			operation:   NewTimeComparator("BETWEEN", clock),
+			arguments:   map[string]interface{}{"From": "2019-10-11T00:00:00Z", "To": "2019-10-12T00:00:00Z"},
+			operand:     dayAgo,
+			want:        true,
+		},
+		{
+			description: "BETWEEN is inclusive",
+			operation:   NewTimeComparator("BETWEEN", clock),
+			arguments:   map[string]interface{}{"From": "2019-10-11T07:20:50Z", "To": "2019-10-12T07:20:50Z"},
+			operand:     now,
+			want:        true,
+		},
+		{
+			description: "BETWEEN expect false",
+			operation:   NewTimeComparator("BETWEEN", clock),
+			arguments:   map[string]interface{}{"From": "2019-10-11T00:00:00Z", "To": "2019-10-12T00:00:00Z"},
+			operand:     now,
+			want:        false,
+		},
+		{
+			description:          "BETWEEN invalid argument, missing To",
+			operation:            NewTimeComparator("BETWEEN", clock),
+			arguments:            map[string]interface{}{"From": "2019-10-11T00:00:00Z"},
+			wantInvalidOperation: true,
+		},
+		{
+			description:          "BETWEEN invalid argument, To before From",
+			operation:            NewTimeComparator("BETWEEN", clock),
+			arguments:            map[string]interface{}{"From": "2019-10-12T00:00:00Z", "To": "2019-10-11T00:00:00Z"},
+			wantInvalidOperation: true,
+		},
+		{
+			description: "BETWEEN Within expect true",
+			operation:   NewTimeComparator("BETWEEN", clock),
+			arguments:   map[string]interface{}{"Within": "24h"},
+			operand:     hourAgo,
+			want:        true,
+		},
+		{
+			description: "BETWEEN Within is inclusive",
+			operation:   NewTimeComparator("BETWEEN", clock),
+			arguments:   map[string]interface{}{"Within": "24h"},
+			operand:     dayAgo,
+			want:        true,
+		},
+		{
+			description: "BETWEEN Within expect false",
+			operation:   NewTimeComparator("BETWEEN", clock),
+			arguments:   map[string]interface{}{"Within": "30m"},
+			operand:     hourAgo,
+			want:        false,
+		},
+		{
+			description: "BETWEEN Within future time expect false",
+			operation:   NewTimeComparator("BETWEEN", clock),
+			arguments:   map[string]interface{}{"Within": "24h"},
+			operand:     now.Add(time.Minute),
+			want:        false,
+		},
+		{
+			description:          "BETWEEN invalid argument, Within not a duration",
+			operation:            NewTimeComparator("BETWEEN", clock),
+			arguments:            map[string]interface{}{"Within": "1 day"},
+			wantInvalidOperation: true,
+		},
+		{
+			description:          "BETWEEN invalid argument, Within negative",
+			operation:            NewTimeComparator("BETWEEN", clock),
+			arguments:            map[string]interface{}{"Within": "-24h"},
+			wantInvalidOperation: true,
+		},
+		{
+			description:          "BETWEEN invalid argument, Within not a string",
+			operation:            NewTimeComparator("BETWEEN", clock),
+			arguments:            map[string]interface{}{"Within": 24},
+			wantInvalidOperation: true,
+		},
+		{
+			description:          "BETWEEN invalid argument, Within with From and To",
+			operation:            NewTimeComparator("BETWEEN", clock),
+			arguments:            map[string]interface{}{"Within": "24h", "From": "2019-10-01T00:00:00Z", "To": "2019-10-02T00:00:00Z"},
+			wantInvalidOperation: true,
+		},
+		{
+			description:          "BETWEEN invalid argument, Within with To",
+			operation:            NewTimeComparator("BETWEEN", clock),
+			arguments:            map[string]interface{}{"Within": "24h", "To": "2019-10-02T00:00:00Z"},
+			wantInvalidOperation: true,
+		},
+		{
+			description:          "Integrer Comparator invalid argument, string value",
+			operation:            NewIntegerComparator(">"),
+			arguments:            map[string]interface{}{"Value": "five"},
+			wantInvalidOperation: true,
+		},
+		{
+			description: "ANY valid and true",
+			operation:   NewQuantifierComparator("ANY"),
+			arguments:   map[string]interface{}{"Filter": map[string]interface{}{"Operation": "==", "Argument": map[string]interface{}{"Value": "b"}}},
+			operand:     []string{"a", "b"},
+			want:        true,
+		},
+		{
+			description: "ANY valid and false, empty list",
+			operation:   NewQuantifierComparator("ANY"),
+			arguments:   map[string]interface{}{"Filter": map[string]interface{}{"Operation": "==", "Argument": map[string]interface{}{"Value": "b"}}},
+			operand:     []string{},
+			want:        false,
+		},
+		{
+			description: "ALL valid and true, struct items",
+			operation:   NewQuantifierComparator("ALL"),
+			arguments:   map[string]interface{}{"Filter": map[string]interface{}{"Field": "id", "Operation": "STARTS WITH", "Argument": map[string]interface{}{"Value": "a"}}},
+			operand:     []TestBase{{Id: "a1"}, {Id: "a2"}},
+			want:        true,
+		},
+		{
+			description: "ALL valid and false",
+			operation:   NewQuantifierComparator("ALL"),
+			arguments:   map[string]interface{}{"Filter": map[string]interface{}{"Operation": ">", "Argument": map[string]interface{}{"Value": 1}}},
+			operand:     []int{1, 2},
+			want:        false,
+		},
+		{
+			description: "ALL valid and true, nil list",
+			operation:   NewQuantifierComparator("ALL"),
+			arguments:   map[string]interface{}{"Filter": map[string]interface{}{"Operation": ">", "Argument": map[string]interface{}{"Value": 1}}},
+			operand:     nil,
+			want:        true,
+		},
+		{
+			description: "NONE valid and true, nested group",
+			operation:   NewQuantifierComparator("NONE"),
+			arguments: map[string]interface{}{"Filter": map[string]interface{}{"Or": []interface{}{
+				map[string]interface{}{"Operation": "==", "Argument": map[string]interface{}{"Value": 1}},
+				map[string]interface{}{"Operation": "==", "Argument": map[string]interface{}{"Value": 2}},
+			}}},
+			operand: []int64{3, 4},
+			want:    true,
+		},
+		{
+			description: "NONE valid and false",
+			operation:   NewQuantifierComparator("NONE"),
+			arguments:   map[string]interface{}{"Filter": map[string]interface{}{"Operation": "==", "Argument": map[string]interface{}{"Value": 4}}},
+			operand:     []int64{3, 4},
+			want:        false,
+		},
+		{
+			description: "ANY invalid, operand is not a list",
+			operation:   NewQuantifierComparator("ANY"),
+			arguments:   map[string]interface{}{"Filter": map[string]interface{}{"Operation": "==", "Argument": map[string]interface{}{"Value": "a"}}},
+			operand:     "a",
+			want:        false,
+		},
+		{
+			description:          "ANY invalid argument, missing filter",
+			operation:            NewQuantifierComparator("ANY"),
+			arguments:            map[string]interface{}{},
+			wantInvalidOperation: true,
+		},
+		{
+			description:          "ANY invalid argument, filter is not an object",
+			operation:            NewQuantifierComparator("ANY"),
+			arguments:            map[string]interface{}{"Filter": "a"},
+			wantInvalidOperation: true,
+		},
+		{
+			description:          "ANY invalid argument, unknown nested operation",
+			operation:            NewQuantifierComparator("ANY"),
+			arguments:            map[string]interface{}{"Filter": map[string]interface{}{"Operation": "bogus"}},
+			wantInvalidOperation: true,
+		},
+		{
+			description: "LENGTH valid and true, default operation",
+			operation:   NewLengthComparator,
+			arguments:   map[string]interface{}{"Value": 2},
+			operand:     []string{"a", "b"},
+			want:        true,
+		},
+		{
+			description: "LENGTH valid and true, nil list",
+			operation:   NewLengthComparator,
+			arguments:   map[string]interface{}{"Value": 0},
+			operand:     nil,
+			want:        true,
+		},
+		{
+			description: "LENGTH valid and true, >=",
+			operation:   NewLengthComparator,
+			arguments:   map[string]interface{}{"Value": 2, "Operation": ">="},
+			operand:     [3]int{},
+			want:        true,
+		},
+		{
+			description: "LENGTH valid and false, !=",
+			operation:   NewLengthComparator,
+			arguments:   map[string]interface{}{"Value": 1, "Operation": "!="},
+			operand:     []int{1},
+			want:        false,
+		},
+		{
+			description: "LENGTH invalid, operand is not a list",
+			operation:   NewLengthComparator,
+			arguments:   map[string]interface{}{"Value": 1},
+			operand:     "a",
+			want:        false,
+		},
+		{
+			description:          "LENGTH invalid argument, float value",
+			operation:            NewLengthComparator,
+			arguments:            map[string]interface{}{"Value": 1.5},
+			wantInvalidOperation: true,
+		},
+		{
+			description:          "LENGTH invalid argument, unknown operation",
+			operation:            NewLengthComparator,
+			arguments:            map[string]interface{}{"Value": 1, "Operation": "CONTAINS"},
+			wantInvalidOperation: true,
+		},
+	}
+
+	for _, test := range tests {
+		op, err := test.operation(test.arguments)
+		switch {
+		case err != nil && test.wantInvalidOperation:
+			continue
+		case err == nil && test.wantInvalidOperation:
+			t.Errorf("Test %q - got nil error want error on invalid operation", test.description)
+			continue
+		case err != nil && !test.wantInvalidOperation:
+			t.Errorf("Test %q - got invalid operation error want nil: %v", test.description, err)
+			continue
+		}
+
+		got := op.Match(test.operand)
+		if got != test.want {
+			t.Errorf("Test %q - got %t, want %t", test.description, got, test.want)
+		}
+	}
+}
+
+func TestCheckType(t *testing.T) {
+	var nilInt *int
+
+	tests := []struct {
+		description string
+		operation   NewListOperation
+		arguments   map[string]interface{}
+		operand     interface{}
+		wantErr     bool
+	}{
+		{
+			description: "string equal, string operand",
+			operation:   NewEqualComparator,
+			arguments:   map[string]interface{}{"Value": "a"},
+			operand:     testKind("b"),
+		},
+		{
+			description: "string equal, int operand",
+			operation:   NewEqualComparator,
+			arguments:   map[string]interface{}{"Value": "a"},
+			operand:     1,
+			wantErr:     true,
+		},
+		{
+			description: "int equal, string operand",
+			operation:   NewEqualComparator,
+			arguments:   map[string]interface{}{"Value": 1},
+			operand:     "1",
+			wantErr:     true,
+		},
+		{
+			description: "int equal, float operand",
+			operation:   NewEqualComparator,
+			arguments:   map[string]interface{}{"Value": 1},
+			operand:     1.5,
+		},
+		{
+			description: "int equal, nil operand",
+			operation:   NewEqualComparator,
+			arguments:   map[string]interface{}{"Value": 1},
+			operand:     nil,
+		},
+		{
+			description: "int equal, nil pointer operand",
+			operation:   NewEqualComparator,
+			arguments:   map[string]interface{}{"Value": 1},
+			operand:     nilInt,
+		},
+		{
+			description: "int equal, struct operand",
+			operation:   NewEqualComparator,
+			arguments:   map[string]interface{}{"Value": 1},
+			operand:     struct{}{},
+			wantErr:     true,
+		},
+		{
+			description: "int not equal, string operand",
+			operation:   NewNotEqualComparator,
+			arguments:   map[string]interface{}{"Value": 1},
+			operand:     "1",
+			wantErr:     true,
+		},
+		{
+			description: "bool equal, int operand",
+			operation:   NewEqualComparator,
+			arguments:   map[string]interface{}{"Value": true},
+			operand:     1,
+			wantErr:     true,
+		},
+		{
+			description: "IN strings, int operand",
+			operation:   NewInComparator,
+			arguments:   map[string]interface{}{"Values": []interface{}{"a"}},
+			operand:     1,
+			wantErr:     true,
+		},
+		{
+			description: "NOT IN integers, string operand",
+			operation:   NewNotInComparator,
+			arguments:   map[string]interface{}{"Values": []interface{}{1}},
+			operand:     "a",
+			wantErr:     true,
+		},
+		{
+			description: "IN empty list, any operand",
+			operation:   NewInComparator,
+			arguments:   map[string]interface{}{"Values": []interface{}{}},
+			operand:     1,
+		},
+		{
+			description: "ANY, string operand",
+			operation:   NewQuantifierComparator("ANY"),
+			arguments:   map[string]interface{}{"Filter": map[string]interface{}{"Operation": "==", "Argument": map[string]interface{}{"Value": "a"}}},
+			operand:     "a",
+			wantErr:     true,
+		},
+		{
+			description: "ANY, item can never match",
+			operation:   NewQuantifierComparator("ANY"),
+			arguments:   map[string]interface{}{"Filter": map[string]interface{}{"Operation": "==", "Argument": map[string]interface{}{"Value": "a"}}},
+			operand:     []int{1},
+			wantErr:     true,
+		},
+		{
+			description: "ALL, item field not found",
+			operation:   NewQuantifierComparator("ALL"),
+			arguments:   map[string]interface{}{"Filter": map[string]interface{}{"Field": "bogus", "Operation": "==", "Argument": map[string]interface{}{"Value": "a"}}},
+			operand:     []TestBase{{Id: "a"}},
+			wantErr:     true,
+		},
+		{
+			description: "NONE, nil operand",
+			operation:   NewQuantifierComparator("NONE"),
+			arguments:   map[string]interface{}{"Filter": map[string]interface{}{"Operation": "==", "Argument": map[string]interface{}{"Value": "a"}}},
+			operand:     nil,
+		},
+		{
+			description: "LENGTH, string operand",
+			operation:   NewLengthComparator,
+			arguments:   map[string]interface{}{"Value": 1},
+			operand:     "a",
+			wantErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		op, err := test.operation(test.arguments)
+		if err != nil {
+			t.Fatalf("Test %q - failed to create operation: %v", test.description, err)
+		}
+		tc, ok := op.(TypeChecker)
+		if !ok {
+			t.Fatalf("Test %q - operation is not a TypeChecker", test.description)
+		}
+
+		err = tc.CheckType(test.operand)
+		if (err != nil) != test.wantErr {
+			t.Errorf("Test %q - got err %v, want err %t", test.description, err, test.wantErr)
+		}
+	}
+}
+
+func TestNullComparator(t *testing.T) {
+	var nilInt *int
+	i := 1
+
+	tests := []struct {
+		description string
+		operation   string
+		operand     interface{}
+		exists      bool
+		want        bool
+	}{
+		{
+			description: "IS NULL, nil",
+			operation:   "IS NULL",
+			operand:     nil,
+			exists:      true,
+			want:        true,
+		},
+		{
+			description: "IS NULL, nil pointer",
+			operation:   "IS NULL",
+			operand:     nilInt,
+			exists:      true,
+			want:        true,
+		},
+		{
+			description: "IS NULL, empty slice",
+			operation:   "IS NULL",
+			operand:     []string{},
+			exists:      true,
+			want:        true,
+		},
+		{
+			description: "IS NULL, empty map",
+			operation:   "IS NULL",
+			operand:     map[string]int{},
+			exists:      true,
+			want:        true,
+		},
+		{
+			description: "IS NULL, missing",
+			operation:   "IS NULL",
+			exists:      false,
+			want:        true,
+		},
+		{
+			description: "IS NULL, pointer",
+			operation:   "IS NULL",
+			operand:     &i,
+			exists:      true,
+			want:        false,
+		},
+		{
+			description: "IS NULL, zero value",
+			operation:   "IS NULL",
+			operand:     "",
+			exists:      true,
+			want:        false,
+		},
+		{
+			description: "IS NOT NULL, slice",
+			operation:   "IS NOT NULL",
+			operand:     []int{1},
+			exists:      true,
+			want:        true,
+		},
+		{
+			description: "IS NOT NULL, nil pointer",
+			operation:   "IS NOT NULL",
+			operand:     nilInt,
+			exists:      true,
+			want:        false,
+		},
+		{
+			description: "IS NOT NULL, missing",
+			operation:   "IS NOT NULL",
+			exists:      false,
+			want:        false,
+		},
+		{
+			description: "EXISTS, nil",
+			operation:   "EXISTS",
+			operand:     nil,
+			exists:      true,
+			want:        true,
+		},
+		{
+			description: "EXISTS, missing",
+			operation:   "EXISTS",
+			exists:      false,
+			want:        false,
+		},
+	}
+
+	for _, test := range tests {
+		op, err := NewNullComparator(test.operation)(nil)
+		if err != nil {
+			t.Fatalf("Test %q - failed to create operation: %v", test.description, err)
+		}
+		pc, ok := op.(PresenceComparator)
+		if !ok {
+			t.Fatalf("Test %q - operation is not a PresenceComparator", test.description)
+		}
+
+		if got := pc.MatchPresence(test.operand, test.exists); got != test.want {
+			t.Errorf("Test %q - got %t, want %t", test.description, got, test.want)
+		}
+		if test.exists {
+			if got := op.Match(test.operand); got != test.want {
+				t.Errorf("Test %q - Match got %t, want %t", test.description, got, test.want)
+			}
+		}
+	}
+}
+
+func TestLimitLengthComparator(t *testing.T) {
+	if _, err := NewLimitLengthComparator(map[string]interface{}{}); err == nil {
+		t.Error("got nil, want an error for a missing Value")
+	}
+
+	c, err := NewLimitLengthComparator(map[string]interface{}{"Value": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []bool
+	for _, item := range []string{"a", "b", "c"} {
+		got = append(got, c.Match(item))
+	}
+	if want := []bool{true, true, false}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
 	}
 }
@@ -0,0 +1,204 @@
+package gql
+
+import (
+	"strings"
+
+	"github.com/GannettDigital/graphql"
+)
+
+// ConnectionMode controls whether the ObjectBuilder builds Relay style connection fields for list fields.
+// See https://relay.dev/graphql/connections.htm for details on connections.
+type ConnectionMode int
+
+const (
+	// ListFields is the default mode, list fields are built only as a GraphQL list.
+	ListFields ConnectionMode = iota
+	// ListAndConnectionFields adds a connection field named '<name>Connection' next to each list field.
+	ListAndConnectionFields
+	// ConnectionFields builds each list field as a connection in place of the GraphQL list.
+	ConnectionFields
+)
+
+const connectionSuffix = "Connection"
+
+// listConnection is the value resolved for a connection field.
+type listConnection struct {
+	edges      []listEdge
+	pageInfo   pageInfo
+	totalCount int
+}
+
+// listEdge is a single item within a listConnection.
+type listEdge struct {
+	node   interface{}
+	cursor string
+}
+
+// pageInfo describes the page of a listConnection.
+type pageInfo struct {
+	hasNextPage     bool
+	hasPreviousPage bool
+	startCursor     string
+	endCursor       string
+}
+
+// SetConnectionMode sets whether list fields are built as Relay style connections, by default they are not.
+// This must be called before BuildInterfaces or BuildTypes.
+func (ob *ObjectBuilder) SetConnectionMode(mode ConnectionMode) {
+	ob.connectionMode = mode
+}
+
+// buildConnection creates the connection object for a list field given the type of items in the list.
+// The connection has the fields 'edges', each with a 'node' and 'cursor', 'pageInfo' and 'totalCount'.
+func (ob *ObjectBuilder) buildConnection(name, parent string, nodeType graphql.Type) *graphql.Object {
+	objectName := strings.ToLower(fullFieldName(name, parent))
+	edge := graphql.NewObject(graphql.ObjectConfig{
+		Name: objectName + "Edge",
+		Fields: graphql.Fields{
+			"node": &graphql.Field{
+				Name: "node",
+				Type: nodeType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(listEdge).node, nil
+				},
+			},
+			"cursor": &graphql.Field{
+				Name:        "cursor",
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "An opaque cursor for the item which can be used with the 'after' argument of the connection.",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(listEdge).cursor, nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: objectName + connectionSuffix,
+		Fields: graphql.Fields{
+			"edges": &graphql.Field{
+				Name: "edges",
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(edge))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*listConnection).edges, nil
+				},
+			},
+			"pageInfo": &graphql.Field{
+				Name: "pageInfo",
+				Type: graphql.NewNonNull(ob.pageInfoObject()),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*listConnection).pageInfo, nil
+				},
+			},
+			"totalCount": &graphql.Field{
+				Name:        "totalCount",
+				Type:        graphql.NewNonNull(graphql.Int),
+				Description: "The length of the list after filtering but before paging.",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*listConnection).totalCount, nil
+				},
+			},
+		},
+	})
+}
+
+// pageInfoObject returns the PageInfo object shared by all connections, it is created on first use.
+func (ob *ObjectBuilder) pageInfoObject() *graphql.Object {
+	if ob.pageInfo != nil {
+		return ob.pageInfo
+	}
+
+	ob.pageInfo = graphql.NewObject(graphql.ObjectConfig{
+		Name: ob.prefix + "PageInfo",
+		Fields: graphql.Fields{
+			"hasNextPage": &graphql.Field{
+				Name: "hasNextPage",
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(pageInfo).hasNextPage, nil
+				},
+			},
+			"hasPreviousPage": &graphql.Field{
+				Name: "hasPreviousPage",
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(pageInfo).hasPreviousPage, nil
+				},
+			},
+			"startCursor": &graphql.Field{
+				Name: "startCursor",
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return nullableString(p.Source.(pageInfo).startCursor), nil
+				},
+			},
+			"endCursor": &graphql.Field{
+				Name: "endCursor",
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return nullableString(p.Source.(pageInfo).endCursor), nil
+				},
+			},
+		},
+	})
+	return ob.pageInfo
+}
+
+// nullableString returns nil for an empty string so it is null in the GraphQL response.
+func nullableString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// ResolveListConnection returns a FieldResolveFn that leverages ResolveByField to get the value of the list field
+// with the given name then returns it as a Relay style connection. The list is sorted and filtered just as with
+// ResolveListField before paging with the 'first' and 'after' or 'offset' and 'limit' arguments. The totalCount of
+// the connection is the length of the list before paging.
+func ResolveListConnection(name string, parent string) graphql.FieldResolveFn {
+	return collectListErrors(func(p graphql.ResolveParams) (interface{}, error) {
+		args, err := parseListArguments(p, name, parent)
+		if err != nil {
+			return nil, err
+		}
+
+		resolve := ResolveByField(name, parent)
+
+		resolvedValue, err := resolve(p)
+		if err != nil {
+			return nil, err
+		}
+
+		var values []interface{}
+		if !isNil(resolvedValue) {
+			values, err = args.sortAndFilter(resolvedValue, name, parent)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		cursors := args.cursors(values)
+		start, end, err := args.page.bounds(cursors)
+		if err != nil {
+			return nil, err
+		}
+		connection := &listConnection{
+			edges:      make([]listEdge, 0, end-start),
+			totalCount: len(values),
+			pageInfo: pageInfo{
+				hasPreviousPage: start > 0,
+				hasNextPage:     end < len(values),
+			},
+		}
+		for i := start; i < end; i++ {
+			connection.edges = append(connection.edges, listEdge{node: values[i], cursor: cursors.cursor(i)})
+		}
+		if len(connection.edges) > 0 {
+			connection.pageInfo.startCursor = connection.edges[0].cursor
+			connection.pageInfo.endCursor = connection.edges[len(connection.edges)-1].cursor
+		}
+
+		return connection, nil
+	})
+}
@@ -0,0 +1,194 @@
+package gql
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/GannettDigital/graphql"
+)
+
+func TestResolveListConnection(t *testing.T) {
+	type item struct {
+		Name  string
+		Value int
+	}
+	type testStruct struct {
+		Items      []item
+		StringList []string `json:"stringList,omitempty"`
+	}
+	testData := testStruct{
+		Items: []item{{Name: "c", Value: 3}, {Name: "a", Value: 1}, {Name: "d", Value: 4}, {Name: "b", Value: 2}},
+	}
+
+	byName := []*sortParameters{{field: "name"}}
+	byValue, err := newListFilter(&listFilterJSON{Field: "value", Operation: ">", Argument: map[string]interface{}{"Value": 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := cursorQuery(byValue, byName)
+	filtered := []interface{}{item{Name: "b", Value: 2}, item{Name: "c", Value: 3}, item{Name: "d", Value: 4}}
+	cursor := func(i int) string { return newListCursors(filtered, byName, query).cursor(i) }
+
+	tests := []struct {
+		description string
+		mode        ConnectionMode
+		query       string
+		variables   map[string]interface{}
+		want        string
+		wantErr     bool
+	}{
+		{
+			description: "Connection next to the list",
+			mode:        ListAndConnectionFields,
+			query:       `query { q { items { name } itemsConnection(first: 2) { totalCount edges { node { name } } pageInfo { hasNextPage hasPreviousPage } } } }`,
+			want:        `{"data":{"q":{"items":[{"name":"c"},{"name":"a"},{"name":"d"},{"name":"b"}],"itemsConnection":{"edges":[{"node":{"name":"c"}},{"node":{"name":"a"}}],"pageInfo":{"hasNextPage":true,"hasPreviousPage":false},"totalCount":4}}}}`,
+		},
+		{
+			description: "Connection with filter, sort and after",
+			mode:        ListAndConnectionFields,
+			query:       `query($c: String) { q { itemsConnection(filter: {Field: "value", Operation: ">", Argument: {Value: 1}}, sort: {Field: "name"}, after: $c) { totalCount edges { node { name } cursor } pageInfo { hasNextPage hasPreviousPage endCursor } } } }`,
+			variables:   map[string]interface{}{"c": cursor(0)},
+			want:        `{"data":{"q":{"itemsConnection":{"edges":[{"cursor":"` + cursor(1) + `","node":{"name":"c"}},{"cursor":"` + cursor(2) + `","node":{"name":"d"}}],"pageInfo":{"endCursor":"` + cursor(2) + `","hasNextPage":false,"hasPreviousPage":true},"totalCount":3}}}}`,
+		},
+		{
+			description: "Connection of a nil list",
+			mode:        ListAndConnectionFields,
+			query:       `query { q { stringListConnection { totalCount edges { node } pageInfo { startCursor } } } }`,
+			want:        `{"data":{"q":{"stringListConnection":{"edges":[],"pageInfo":{"startCursor":null},"totalCount":0}}}}`,
+		},
+		{
+			description: "Connection in place of the list",
+			mode:        ConnectionFields,
+			query:       `query { q { items(offset: 3) { totalCount edges { node { name } } } } }`,
+			want:        `{"data":{"q":{"items":{"edges":[{"node":{"name":"b"}}],"totalCount":4}}}}`,
+		},
+		{
+			description: "No connection by default",
+			mode:        ListFields,
+			query:       `query { q { itemsConnection { totalCount } } }`,
+			wantErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		ob, err := NewObjectBuilder([]interface{}{testStruct{}}, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ob.SetConnectionMode(test.mode)
+		types := ob.BuildTypes()
+		s, err := graphql.NewSchema(graphql.SchemaConfig{
+			Query: graphql.NewObject(graphql.ObjectConfig{
+				Name: "Query",
+				Fields: graphql.Fields{
+					"q": &graphql.Field{
+						Type: types[0],
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							return testData, nil
+						},
+					},
+				},
+			}),
+			Types: types,
+		})
+		if err != nil {
+			t.Fatalf("Test %q - failed to build schema: %v", test.description, err)
+		}
+
+		resp := graphql.Do(graphql.Params{Context: context.Background(), Schema: s, RequestString: test.query, VariableValues: test.variables})
+		if gotErr := len(resp.Errors) != 0; gotErr != test.wantErr {
+			t.Errorf("Test %q - got errors %v, want error %t", test.description, resp.Errors, test.wantErr)
+		}
+		if test.wantErr {
+			continue
+		}
+
+		gotBytes, err := json.Marshal(resp)
+		if err != nil {
+			t.Errorf("Test %q - failed to Marshal: %v", test.description, err)
+		}
+		if got, want := string(gotBytes), test.want; got != want {
+			t.Errorf("Test %q - got %v, want %v", test.description, got, want)
+		}
+	}
+}
+
+func TestConnectionPaging(t *testing.T) {
+	type item struct {
+		Name  string
+		Value int
+	}
+	type testStruct struct {
+		Items []item
+	}
+	testData := testStruct{
+		Items: []item{{Name: "c", Value: 3}, {Name: "e", Value: 5}, {Name: "d", Value: 4}, {Name: "b", Value: 2}},
+	}
+
+	ob, err := NewObjectBuilder([]interface{}{testStruct{}}, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ob.SetConnectionMode(ConnectionFields)
+	types := ob.BuildTypes()
+	s, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"q": &graphql.Field{
+					Type: types[0],
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return testData, nil
+					},
+				},
+			},
+		}),
+		Types: types,
+	})
+	if err != nil {
+		t.Fatalf("failed to build schema: %v", err)
+	}
+
+	page := func(filter, after string) (names []string, endCursor string, err error) {
+		query := `query($c: String) { q { items(filter: ` + filter + `, sort: {Field: "name"}, first: 2, after: $c) { edges { node { name } } pageInfo { endCursor } } } }`
+		var variables map[string]interface{}
+		if after != "" {
+			variables = map[string]interface{}{"c": after}
+		}
+		resp := graphql.Do(graphql.Params{Context: context.Background(), Schema: s, RequestString: query, VariableValues: variables})
+		if len(resp.Errors) != 0 {
+			return nil, "", resp.Errors[0]
+		}
+		items := resp.Data.(map[string]interface{})["q"].(map[string]interface{})["items"].(map[string]interface{})
+		for _, edge := range items["edges"].([]interface{}) {
+			names = append(names, edge.(map[string]interface{})["node"].(map[string]interface{})["name"].(string))
+		}
+		endCursor, _ = items["pageInfo"].(map[string]interface{})["endCursor"].(string)
+		return names, endCursor, nil
+	}
+	valueFilter := `{Field: "value", Operation: ">", Argument: {Value: 1}}`
+
+	names, cursor, err := page(valueFilter, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Join(names, ","), "b,c"; got != want {
+		t.Errorf("first page - got %v, want %v", got, want)
+	}
+
+	// An item added before the cursor and the cursor item removed don't change the items on the next page.
+	testData.Items = []item{{Name: "e", Value: 5}, {Name: "d", Value: 4}, {Name: "b", Value: 2}, {Name: "a", Value: 2}}
+	names, _, err = page(valueFilter, cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Join(names, ","), "d,e"; got != want {
+		t.Errorf("next page - got %v, want %v", got, want)
+	}
+
+	if _, _, err := page(`{Field: "value", Operation: ">", Argument: {Value: 2}}`, cursor); err == nil {
+		t.Error("cursor with a different filter - got no error")
+	}
+}
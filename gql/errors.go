@@ -0,0 +1,205 @@
+package gql
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/GannettDigital/graphql"
+	"github.com/GannettDigital/graphql/gqlerrors"
+	"github.com/GannettDigital/graphql/language/location"
+)
+
+// ErrorCollectorContextKey is the key used with context.WithValue to locate the ErrorCollector.
+const ErrorCollectorContextKey = "GraphQLErrorCollector"
+
+// ListErrorCode identifies the kind of failure described by a FilterError or SortError.
+type ListErrorCode string
+
+const (
+	// InvalidFilterCode is used when a filter is malformed, for instance it defines both an Operation and a group.
+	InvalidFilterCode ListErrorCode = "INVALID_FILTER"
+	// UnknownOperationCode is used when the Operation of a filter is not in ListOperations or ListTransforms.
+	UnknownOperationCode ListErrorCode = "UNKNOWN_OPERATION"
+	// InvalidArgumentCode is used when an operation rejects the Argument of a filter.
+	InvalidArgumentCode ListErrorCode = "INVALID_ARGUMENT"
+	// OperationFailedCode is used when an operation on the whole list fails.
+	OperationFailedCode ListErrorCode = "OPERATION_FAILED"
+	// InvalidSortCode is used when a sort is malformed, for instance it has an unknown Order.
+	InvalidSortCode ListErrorCode = "INVALID_SORT"
+	// FieldNotFoundCode is used when the Field of a filter or sort is not found in the list items.
+	FieldNotFoundCode ListErrorCode = "FIELD_NOT_FOUND"
+	// TypeMismatchCode is used when the values of a field can't be filtered or sorted, for instance a filter comparing a
+	// number with a string field or a sort of a field holding both numbers and strings.
+	TypeMismatchCode ListErrorCode = "TYPE_MISMATCH"
+)
+
+// FilterError is the error returned when the filter argument of a list field is invalid or can't be applied.
+// Field is the path of the field filtered, empty for the list items themselves or if the error is not specific to one
+// field, and Operation the operation of the filter if known.
+type FilterError struct {
+	Code      ListErrorCode
+	Field     string
+	Operation string
+	Err       error
+}
+
+func (e *FilterError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *FilterError) Unwrap() error {
+	return e.Err
+}
+
+// Extensions returns the details of the error for inclusion in the extensions of a GraphQL error.
+func (e *FilterError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":      string(e.Code),
+		"argument":  filterArgumentName,
+		"field":     e.Field,
+		"operation": e.Operation,
+	}
+}
+
+// SortError is the error returned when the sort argument of a list field is invalid or the list can't be sorted.
+// Field is the path of the field of the sort key which failed, empty for the list items themselves or if the error is
+// not specific to one sort key, and Order the order of that sort key.
+type SortError struct {
+	Code  ListErrorCode
+	Field string
+	Order string
+	Err   error
+}
+
+func (e *SortError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *SortError) Unwrap() error {
+	return e.Err
+}
+
+// Extensions returns the details of the error for inclusion in the extensions of a GraphQL error.
+func (e *SortError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":     string(e.Code),
+		"argument": sortArgumentName,
+		"field":    e.Field,
+		"order":    e.Order,
+	}
+}
+
+// FormattedError is a GraphQL error including extensions, see http://spec.graphql.org/June2018/#sec-Errors
+type FormattedError struct {
+	Message    string                    `json:"message"`
+	Locations  []location.SourceLocation `json:"locations"`
+	Extensions map[string]interface{}    `json:"extensions,omitempty"`
+}
+
+// ErrorCollector collects the FilterError and SortError errors returned by the list resolvers so they can be added
+// to the errors of a GraphQL result as extensions, the GraphQL library keeps only the message and locations of the
+// errors returned by resolvers. Add an ErrorCollector to the request context using ErrorCollectorContextKey as the
+// context value key then after the query pass the errors of the result to FormatErrors.
+// An ErrorCollector is concurrency safe but should only be used for a single query.
+type ErrorCollector struct {
+	mux    sync.Mutex
+	errors []collectedError
+}
+
+// collectedError is an error returned by a list resolver with the locations of the field as reported in the GraphQL
+// error and the extensions to add to it.
+type collectedError struct {
+	message    string
+	locations  []location.SourceLocation
+	extensions map[string]interface{}
+}
+
+// NewErrorCollector returns an empty ErrorCollector.
+func NewErrorCollector() *ErrorCollector {
+	return &ErrorCollector{}
+}
+
+// FormatErrors returns the given errors with the extensions of the matching FilterError or SortError added. The
+// extensions include the 'code', the name of the 'argument' and its 'location' in the query along with the 'field' and
+// the 'operation' or 'order' of the error.
+func (ec *ErrorCollector) FormatErrors(errs []gqlerrors.FormattedError) []FormattedError {
+	ec.mux.Lock()
+	defer ec.mux.Unlock()
+
+	formatted := make([]FormattedError, len(errs))
+	for i, err := range errs {
+		formatted[i] = FormattedError{Message: err.Message, Locations: err.Locations}
+		for _, collected := range ec.errors {
+			if collected.message == err.Message && sameLocations(collected.locations, err.Locations) {
+				formatted[i].Extensions = collected.extensions
+				break
+			}
+		}
+	}
+	return formatted
+}
+
+// collect adds a located FilterError or SortError along with its extensions. The location of the argument of the
+// error is added to the extensions.
+func (ec *ErrorCollector) collect(p graphql.ResolveParams, err *gqlerrors.Error, extensions map[string]interface{}) {
+	for _, field := range p.Info.FieldASTs {
+		for _, arg := range field.Arguments {
+			if arg.Name != nil && arg.Name.Value == extensions["argument"] && arg.Loc != nil {
+				extensions["location"] = location.GetLocation(arg.Loc.Source, arg.Loc.Start)
+			}
+		}
+	}
+
+	ec.mux.Lock()
+	ec.errors = append(ec.errors, collectedError{message: err.Message, locations: err.Locations, extensions: extensions})
+	ec.mux.Unlock()
+}
+
+// listErrorExtensions returns the extensions of the error if it is a FilterError or SortError, otherwise nil.
+func listErrorExtensions(err error) map[string]interface{} {
+	var fe *FilterError
+	if errors.As(err, &fe) {
+		return fe.Extensions()
+	}
+	var se *SortError
+	if errors.As(err, &se) {
+		return se.Extensions()
+	}
+	return nil
+}
+
+// sameLocations returns true if both lists of locations are the same.
+func sameLocations(a, b []location.SourceLocation) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// collectListErrors wraps the resolve function of a list field so any FilterError or SortError it returns is located
+// at the field in the query and added to the ErrorCollector if one is found in the context.
+func collectListErrors(resolve graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		value, err := resolve(p)
+		if err == nil {
+			return value, nil
+		}
+		extensions := listErrorExtensions(err)
+		if extensions == nil {
+			return value, err
+		}
+
+		located := gqlerrors.NewLocatedError(err, gqlerrors.FieldASTsToNodeASTs(p.Info.FieldASTs))
+		if ec, ok := p.Context.Value(ErrorCollectorContextKey).(*ErrorCollector); ok && ec != nil {
+			ec.collect(p, located, extensions)
+		}
+		return value, located
+	}
+}
@@ -0,0 +1,199 @@
+package gql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/GannettDigital/graphql"
+)
+
+func TestFilterErrors(t *testing.T) {
+	type item struct {
+		Name  string
+		Value int
+	}
+	list := []interface{}{item{Name: "a", Value: 1}, item{Name: "b", Value: 2}}
+
+	tests := []struct {
+		description string
+		filter      *listFilterJSON
+		want        *FilterError
+	}{
+		{
+			description: "Operation and group",
+			filter:      &listFilterJSON{Field: "name", Operation: "==", Not: &listFilterJSON{Operation: "IS NULL"}},
+			want:        &FilterError{Code: InvalidFilterCode, Field: "name", Operation: "=="},
+		},
+		{
+			description: "Unknown operation within a group",
+			filter:      &listFilterJSON{Or: []*listFilterJSON{{Field: "value", Operation: "ABOUT"}}},
+			want:        &FilterError{Code: UnknownOperationCode, Field: "value", Operation: "ABOUT"},
+		},
+		{
+			description: "Invalid argument",
+			filter:      &listFilterJSON{Field: "value", Operation: ">", Argument: map[string]interface{}{"Value": "a"}},
+			want:        &FilterError{Code: InvalidArgumentCode, Field: "value", Operation: ">"},
+		},
+		{
+			description: "Invalid transform argument",
+			filter:      &listFilterJSON{Operation: "LIMIT"},
+			want:        &FilterError{Code: InvalidArgumentCode, Operation: "LIMIT"},
+		},
+		{
+			description: "Field not found",
+			filter:      &listFilterJSON{Field: "missing", Operation: "==", Argument: map[string]interface{}{"Value": "a"}},
+			want:        &FilterError{Code: FieldNotFoundCode, Field: "missing"},
+		},
+		{
+			description: "Field not found with a presence operation",
+			filter:      &listFilterJSON{Not: &listFilterJSON{Field: "missing", Operation: "IS NULL"}},
+			want:        &FilterError{Code: FieldNotFoundCode, Field: "missing"},
+		},
+		{
+			description: "Type mismatch",
+			filter:      &listFilterJSON{Field: "name", Operation: "==", Argument: map[string]interface{}{"Value": 1}},
+			want:        &FilterError{Code: TypeMismatchCode, Field: "name", Operation: "=="},
+		},
+		{
+			description: "Transform field given twice",
+			filter:      &listFilterJSON{Field: "name", Operation: "DISTINCT", Argument: map[string]interface{}{"Field": "name"}},
+			want:        &FilterError{Code: InvalidFilterCode, Field: "name", Operation: "DISTINCT"},
+		},
+		{
+			description: "Transform failure",
+			filter:      &listFilterJSON{Operation: "DISTINCT", Argument: map[string]interface{}{"Field": "missing"}},
+			want:        &FilterError{Code: OperationFailedCode, Operation: "DISTINCT"},
+		},
+	}
+
+	for _, test := range tests {
+		filter, err := newListFilter(test.filter)
+		if err == nil {
+			_, err = filter.apply(list)
+		}
+		var got *FilterError
+		if !errors.As(err, &got) {
+			t.Errorf("Test %q - got error %v, want a FilterError", test.description, err)
+			continue
+		}
+		if got.Code != test.want.Code || got.Field != test.want.Field || got.Operation != test.want.Operation {
+			t.Errorf("Test %q - got %q %q %q, want %q %q %q", test.description, got.Code, got.Field, got.Operation,
+				test.want.Code, test.want.Field, test.want.Operation)
+		}
+	}
+}
+
+func TestSortErrors(t *testing.T) {
+	type item struct {
+		Name  string
+		Value interface{}
+	}
+	list := []interface{}{item{Name: "a", Value: 1}, item{Name: "b", Value: "2"}}
+
+	tests := []struct {
+		description string
+		sort        interface{}
+		want        *SortError
+	}{
+		{
+			description: "Invalid order",
+			sort:        []*sortParameters{{field: "name", order: "UP"}},
+			want:        &SortError{Code: InvalidSortCode},
+		},
+		{
+			description: "Invalid sort",
+			sort:        "name",
+			want:        &SortError{Code: InvalidSortCode},
+		},
+		{
+			description: "Field not found",
+			sort:        []*sortParameters{{field: "name"}, {field: "missing", order: descending}},
+			want:        &SortError{Code: FieldNotFoundCode, Field: "missing", Order: descending},
+		},
+		{
+			description: "Unsortable type",
+			sort:        []*sortParameters{{}},
+			want:        &SortError{Code: TypeMismatchCode},
+		},
+		{
+			description: "Mixed types",
+			sort:        []*sortParameters{{field: "value", order: ascending}},
+			want:        &SortError{Code: TypeMismatchCode, Field: "value", Order: ascending},
+		},
+	}
+
+	for _, test := range tests {
+		params, err := parseSortParameters(test.sort)
+		if err == nil {
+			err = listSort(params, append([]interface{}{}, list...))
+		}
+		var got *SortError
+		if !errors.As(err, &got) {
+			t.Errorf("Test %q - got error %v, want a SortError", test.description, err)
+			continue
+		}
+		if got.Code != test.want.Code || got.Field != test.want.Field || got.Order != test.want.Order {
+			t.Errorf("Test %q - got %q %q %q, want %q %q %q", test.description, got.Code, got.Field, got.Order,
+				test.want.Code, test.want.Field, test.want.Order)
+		}
+	}
+}
+
+func TestErrorCollector(t *testing.T) {
+	s := testSchema(t)
+
+	tests := []struct {
+		description string
+		query       string
+		want        string
+	}{
+		{
+			description: "Filter error",
+			query: `query { q(id: "1") {
+  items(filter: {Field: "value", Operation: "ABOUT"}) { name }
+} }`,
+			want: `[{"message":"unknown filter operator \"ABOUT\"","locations":[{"line":2,"column":3}],"extensions":{"argument":"filter","code":"UNKNOWN_OPERATION","field":"value","location":{"line":2,"column":9},"operation":"ABOUT"}}]`,
+		},
+		{
+			description: "Sort error",
+			query:       `query { q(id: "1") { filteredTotalItems itemsGroups(sort: {Field: "leaf"}) { count } } }`,
+			want:        `[{"message":"unknown type for sort field \"leaf\"","locations":[{"line":1,"column":41}],"extensions":{"argument":"sort","code":"TYPE_MISMATCH","field":"leaf","location":{"line":1,"column":53},"order":""}}]`,
+		},
+		{
+			description: "Other error",
+			query:       `query { q(id: "bad-total-count") { totalItems } }`,
+			want:        `[{"message":"field value is not a valid list in the data","locations":[{"line":1,"column":36}]}]`,
+		},
+	}
+
+	for _, test := range tests {
+		ec := NewErrorCollector()
+		ctx := context.WithValue(context.Background(), ErrorCollectorContextKey, ec)
+		resp := graphql.Do(graphql.Params{Context: ctx, Schema: s, RequestString: test.query})
+
+		gotBytes, err := json.Marshal(ec.FormatErrors(resp.Errors))
+		if err != nil {
+			t.Errorf("Test %q - failed to Marshal: %v", test.description, err)
+		}
+		if got, want := string(gotBytes), test.want; got != want {
+			t.Errorf("Test %q - got %v, want %v", test.description, got, want)
+		}
+	}
+}
+
+func TestErrorExtensions(t *testing.T) {
+	fe := &FilterError{Code: TypeMismatchCode, Field: "a_b", Operation: "==", Err: errors.New("bad")}
+	want := map[string]interface{}{"code": "TYPE_MISMATCH", "argument": "filter", "field": "a_b", "operation": "=="}
+	if got := fe.Extensions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	se := &SortError{Code: InvalidSortCode, Err: errors.New("bad")}
+	want = map[string]interface{}{"code": "INVALID_SORT", "argument": "sort", "field": "", "order": ""}
+	if got := se.Extensions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -3,8 +3,11 @@ package gql
 import (
 	"errors"
 	"fmt"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/GannettDigital/graphql"
 	"github.com/GannettDigital/graphql/language/ast"
@@ -16,35 +19,74 @@ var (
 	// The string is the operation, ie '==' which matches a particular NewListOperation function.
 	// The default implementations are pre-populated and additional operations can be added before running the
 	// ObjectBuilder.
+	// The comparator is given the value of the filter's Field in each list item, if Field is empty it is given the list
+	// item itself so an operation can compare lists of scalars or whole items.
 	ListOperations = map[string]NewListOperation{
 		"==":     NewEqualComparator,
 		"!=":     NewNotEqualComparator,
-		">":      NewIntegerComparator(">"),
-		">=":     NewIntegerComparator(">="),
-		"<":      NewIntegerComparator("<"),
-		"<=":     NewIntegerComparator("<="),
-		"LIMIT":  NewLimitLengthComparator,
+		">":      NewNumberComparator(">"),
+		">=":     NewNumberComparator(">="),
+		"<":      NewNumberComparator("<"),
+		"<=":     NewNumberComparator("<="),
 		"IN":     NewInComparator,
 		"NOT IN": NewNotInComparator,
+
+		stringContains:             NewStringComparator(stringContains, false),
+		stringStartsWith:           NewStringComparator(stringStartsWith, false),
+		stringEndsWith:             NewStringComparator(stringEndsWith, false),
+		stringMatches:              NewMatchComparator,
+		stringEqualsIgnoreCase:     NewStringComparator(stringEquals, true),
+		stringContainsIgnoreCase:   NewStringComparator(stringContains, true),
+		stringStartsWithIgnoreCase: NewStringComparator(stringStartsWith, true),
+		stringEndsWithIgnoreCase:   NewStringComparator(stringEndsWith, true),
+
+		timeBefore:  NewTimeComparator(timeBefore, time.Now),
+		timeAfter:   NewTimeComparator(timeAfter, time.Now),
+		timeBetween: NewTimeComparator(timeBetween, time.Now),
+
+		nullIs:    NewNullComparator(nullIs),
+		nullIsNot: NewNullComparator(nullIsNot),
+		nullExist: NewNullComparator(nullExist),
+
+		listLength: NewLengthComparator,
 	}
 
 	graphqlListFilter = graphql.NewScalar(graphql.ScalarConfig{
 		Name:         "ListFilter",
-		Description:  "A JSON object used for filtering list items, includes a required field 'Operation' and optional fields 'Argument' and 'Field'.",
+		Description:  "A JSON object used for filtering list items, includes a required field 'Operation' and optional fields 'Argument' and 'Field', or alternatively a single 'And', 'Or' or 'Not' grouping other filters.",
 		Serialize:    func(value interface{}) interface{} { return nil },
-		ParseValue:   func(value interface{}) interface{} { return nil },
+		ParseValue:   parseListFilterValue,
 		ParseLiteral: func(valueAST ast.Value) interface{} { return valueAST.GetValue() },
 	})
 )
 
+func init() {
+	// The quantifier operations build nested filters which look up their operations in ListOperations so they are
+	// added here to avoid an initialization cycle.
+	ListOperations[quantifierAny] = NewQuantifierComparator(quantifierAny)
+	ListOperations[quantifierAll] = NewQuantifierComparator(quantifierAll)
+	ListOperations[quantifierNone] = NewQuantifierComparator(quantifierNone)
+}
+
 // NewListOperation is a function which returns a Comparator given a set of list filter arguments.
 type NewListOperation func(argument map[string]interface{}) (Comparator, error)
 
+const (
+	filterAnd = "And"
+	filterOr  = "Or"
+	filterNot = "Not"
+)
+
 // listFilterJSON represents a list filter as defined as a JSON object.
+// A list filter is either a single operation defined by Field, Operation and Argument or a boolean group of other
+// list filters defined by exactly one of And, Or or Not.
 type listFilterJSON struct {
 	Field     string                 `json:"Field,omitempty"`
 	Operation string                 `json:"Operation"`
 	Argument  map[string]interface{} `json:"Argument,omitempty"`
+	And       []*listFilterJSON      `json:"And,omitempty"`
+	Or        []*listFilterJSON      `json:"Or,omitempty"`
+	Not       *listFilterJSON        `json:"Not,omitempty"`
 }
 
 // newListFilterJSON will parse an AST ObjectField returning the listFilterJSON	found within it.
@@ -80,13 +122,91 @@ func newListFilterJSON(fields []*ast.ObjectField) (*listFilterJSON, error) {
 				argfields[field.Name.Value] = value
 			}
 			lf.Argument = argfields
+		case filterAnd, filterOr:
+			list, ok := f.GetValue().(*ast.ListValue)
+			if !ok {
+				return nil, fmt.Errorf("unable to parse filter argument field %s", f.Name.Value)
+			}
+			children := []*listFilterJSON{}
+			for _, item := range list.Values {
+				obj, ok := item.(*ast.ObjectValue)
+				if !ok {
+					return nil, fmt.Errorf("unable to parse filter argument field %s, each item must be a filter object", f.Name.Value)
+				}
+				child, err := newListFilterJSON(obj.Fields)
+				if err != nil {
+					return nil, err
+				}
+				children = append(children, child)
+			}
+			if f.Name.Value == filterAnd {
+				lf.And = children
+			} else {
+				lf.Or = children
+			}
+		case filterNot:
+			obj, ok := f.GetValue().(*ast.ObjectValue)
+			if !ok {
+				return nil, errors.New("unable to parse filter argument field Not")
+			}
+			child, err := newListFilterJSON(obj.Fields)
+			if err != nil {
+				return nil, err
+			}
+			lf.Not = child
 		}
 	}
 
 	return &lf, nil
 }
 
+// parseListFilterValue is the graphql.ParseValueFn for the ListFilter scalar, it is used when the filter is given
+// as a query variable rather than inline. The variable is decoded into a listFilterJSON, if that fails nil is returned
+// which the GraphQL library reports as an invalid variable value.
+func parseListFilterValue(value interface{}) interface{} {
+	var lf listFilterJSON
+	if err := decodeVariable(value, &lf); err != nil {
+		return nil
+	}
+	lf.normalizeArguments()
+	return &lf
+}
+
+// isTransform returns true if the filter is a single operation found in ListTransforms.
+func (lf *listFilterJSON) isTransform() bool {
+	if lf == nil || lf.And != nil || lf.Or != nil || lf.Not != nil {
+		return false
+	}
+	_, ok := ListTransforms[lf.Operation]
+	return ok
+}
+
+// normalizeArguments runs normalizeJSONValue on the Argument of this filter and any filters grouped within it.
+func (lf *listFilterJSON) normalizeArguments() {
+	for key, arg := range lf.Argument {
+		lf.Argument[key] = normalizeJSONValue(arg)
+	}
+	for _, child := range lf.And {
+		child.normalizeArguments()
+	}
+	for _, child := range lf.Or {
+		child.normalizeArguments()
+	}
+	if lf.Not != nil {
+		lf.Not.normalizeArguments()
+	}
+}
+
+// String describes the filter, for a boolean group this includes all filters within the group.
 func (lf listFilterJSON) String() string {
+	switch {
+	case lf.And != nil:
+		return joinListFilters(lf.And, " AND ")
+	case lf.Or != nil:
+		return joinListFilters(lf.Or, " OR ")
+	case lf.Not != nil:
+		return fmt.Sprintf("NOT (%v)", lf.Not)
+	}
 	var arguments []string
 	for _, arg := range lf.Argument {
 		arguments = append(arguments, fmt.Sprintf("%v", arg))
@@ -94,15 +214,36 @@ func (lf listFilterJSON) String() string {
 	return fmt.Sprintf("Field:%v, Operation:%v, Arguments:%v", lf.Field, lf.Operation, strings.Join(arguments, ","))
 }
 
+func joinListFilters(filters []*listFilterJSON, separator string) string {
+	parts := make([]string, len(filters))
+	for i, f := range filters {
+		parts[i] = fmt.Sprintf("(%v)", f)
+	}
+	return strings.Join(parts, separator)
+}
+
 // parseASTValue will recursively follow a AST value structure to build up a Golang object.
+// Strings and enums become a string, integers an int, floats a float64, booleans a bool, lists an []interface{} and
+// objects a map[string]interface{}. A missing value is returned as nil, note the GraphQL parser itself does not
+// accept a literal null so an explicit null can only be given via a variable.
 func parseASTValue(in interface{}) (interface{}, error) {
+	if in == nil {
+		return nil, nil
+	}
 	value, ok := in.(ast.Value)
 	if !ok {
 		return nil, errors.New("unable to parse filter argument")
 	}
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil, nil
+	}
 	switch value.GetKind() {
-	case kinds.StringValue:
-		return value.GetValue(), nil
+	case kinds.StringValue, kinds.EnumValue:
+		strValue, ok := value.GetValue().(string)
+		if !ok {
+			return nil, errors.New("unable to determine value of string")
+		}
+		return strValue, nil
 	case kinds.IntValue:
 		// It isn't clear why the GraphQL library is written this way but the Value of a Intfield is stored as
 		// a string so if needed convert
@@ -118,6 +259,26 @@ func parseASTValue(in interface{}) (interface{}, error) {
 			return nil, errors.New("unable to determine value of integer")
 		}
 		return realValue, nil
+	case kinds.FloatValue:
+		// Just as with integers the Value of a float is stored as a string
+		strValue, ok := value.GetValue().(string)
+		if !ok {
+			if _, ok := value.GetValue().(float64); ok {
+				return value.GetValue(), nil
+			}
+			return nil, errors.New("unable to determine value of float")
+		}
+		realValue, err := strconv.ParseFloat(strValue, 64)
+		if err != nil {
+			return nil, errors.New("unable to determine value of float")
+		}
+		return realValue, nil
+	case kinds.BooleanValue:
+		boolValue, ok := value.GetValue().(bool)
+		if !ok {
+			return nil, errors.New("unable to determine value of boolean")
+		}
+		return boolValue, nil
 	case kinds.ListValue:
 		v, ok := value.(*ast.ListValue)
 		if !ok {
@@ -132,59 +293,333 @@ func parseASTValue(in interface{}) (interface{}, error) {
 			list = append(list, itemValue)
 		}
 		return list, nil
+	case kinds.ObjectValue:
+		v, ok := value.(*ast.ObjectValue)
+		if !ok {
+			return nil, errors.New("failed to parse AST object")
+		}
+		object := make(map[string]interface{})
+		for _, field := range v.Fields {
+			fieldValue, err := parseASTValue(field.GetValue())
+			if err != nil {
+				return nil, fmt.Errorf("%s -> %v", field.Name.Value, err)
+			}
+			object[field.Name.Value] = fieldValue
+		}
+		return object, nil
+	case kinds.Variable:
+		return nil, errors.New("variables within a filter are not supported, pass the entire filter as a variable")
 	default:
 		return nil, fmt.Errorf("unhandled AST value type %q", value.GetKind())
 	}
 }
 
-// An listFilter is able to filter values in an array only returning one which match its comparator.
+// An listFilter is able to filter values in an array only returning one which match its comparator, the filtered list
+// is then passed through any ListTransforms in the filter.
+// The values of all fields referenced by the filter are extracted from each item first and then the comparator,
+// which may be a tree of AndComparator, OrComparator and NotComparator, is matched against those values.
+// Before matching each field value is checked by any operation comparators which implement TypeChecker.
+// Fields which can't be found are left out of the values. A field missing from the list item itself is an error
+// unless the item is a document, a map or json.RawMessage which has no fixed set of fields, and the field is only
+// referenced by operations which implement PresenceComparator. So a misspelled field on a struct is always an error.
 type listFilter struct {
-	fieldName string
-	op        Comparator
-	json      *listFilterJSON
+	fieldNames []string
+	paths      map[string]fieldPath
+	required   map[string]bool
+	checks     []fieldComparator
+	op         Comparator
+	transforms []namedTransform
+	json       *listFilterJSON
 }
 
 // newListFilter parses a given argument into a listFilter. The type of listFilter returned is based on the operation.
+// The argument is either the AST object fields of an inline filter, the listFilterJSON decoded from a variable or the
+// map of a typed filter input object.
 func newListFilter(arg interface{}) (*listFilter, error) {
-	if arg == nil {
+	var lf *listFilterJSON
+	switch arg := arg.(type) {
+	case nil:
 		return nil, nil
+	case []*ast.ObjectField:
+		var err error
+		lf, err = newListFilterJSON(arg)
+		if err != nil {
+			return nil, &FilterError{Code: InvalidFilterCode, Err: err}
+		}
+	case *listFilterJSON:
+		lf = arg
+	case map[string]interface{}:
+		if err := decodeVariable(arg, &lf); err != nil {
+			return nil, &FilterError{Code: InvalidFilterCode, Err: fmt.Errorf("unable to parse filter argument: %v", err)}
+		}
+		lf.normalizeArguments()
+	default:
+		return nil, &FilterError{Code: InvalidFilterCode, Err: errors.New("unable to parse filter argument")}
 	}
-	fields, ok := arg.([]*ast.ObjectField)
-	if !ok {
-		return nil, errors.New("unable to parse filter argument")
+
+	filter := &listFilter{json: lf, paths: make(map[string]fieldPath), required: make(map[string]bool)}
+
+	// ListTransforms at the top level or within a top level And are split out of the filter to be run on the list
+	// after filtering.
+	root := lf
+	var stages []*listFilterJSON
+	switch {
+	case lf.isTransform():
+		root = nil
+		stages = append(stages, lf)
+	case len(lf.And) > 0 && lf.Operation == "" && lf.Or == nil && lf.Not == nil:
+		var children []*listFilterJSON
+		for _, child := range lf.And {
+			if child.isTransform() {
+				stages = append(stages, child)
+			} else {
+				children = append(children, child)
+			}
+		}
+		switch len(children) {
+		case 0:
+			root = nil
+		case 1:
+			root = children[0]
+		default:
+			root = &listFilterJSON{And: children}
+		}
+	}
+	for _, stage := range stages {
+		arg, err := transformArgument(stage)
+		if err != nil {
+			return nil, err
+		}
+		transform, err := ListTransforms[stage.Operation](arg)
+		if err != nil {
+			return nil, &FilterError{Code: InvalidArgumentCode, Field: stage.Field, Operation: stage.Operation, Err: err}
+		}
+		filter.transforms = append(filter.transforms, namedTransform{operation: stage.Operation, transform: transform})
+	}
+	if root == nil {
+		return filter, nil
 	}
 
-	lf, err := newListFilterJSON(fields)
+	var leaves []fieldComparator
+	op, err := newFilterComparator(root, &leaves)
 	if err != nil {
 		return nil, err
 	}
+	filter.op = op
+
+	fields := make(map[string]bool)
+	for _, leaf := range leaves {
+		if !fields[leaf.fieldName] {
+			fields[leaf.fieldName] = true
+			filter.fieldNames = append(filter.fieldNames, leaf.fieldName)
+			filter.paths[leaf.fieldName] = compileFieldPath(leaf.fieldName)
+		}
+		if _, ok := leaf.op.(PresenceComparator); !ok {
+			filter.required[leaf.fieldName] = true
+		}
+		if _, ok := leaf.op.(TypeChecker); ok {
+			filter.checks = append(filter.checks, leaf)
+		}
+	}
+	sort.Strings(filter.fieldNames)
+
+	return filter, nil
+}
+
+// newFilterComparator recursively builds the Comparator for the given filter. The comparator for each operation is
+// added to leaves so the values of the fields can be extracted and checked before matching.
+func newFilterComparator(lf *listFilterJSON, leaves *[]fieldComparator) (Comparator, error) {
+	if lf == nil {
+		return nil, &FilterError{Code: InvalidFilterCode, Err: errors.New("filter is undefined")}
+	}
+
+	var groups int
+	for _, isSet := range []bool{lf.And != nil, lf.Or != nil, lf.Not != nil} {
+		if isSet {
+			groups++
+		}
+	}
+	isOperation := lf.Operation != "" || lf.Field != "" || lf.Argument != nil
+	if groups > 1 || (groups == 1 && isOperation) {
+		return nil, &FilterError{
+			Code:      InvalidFilterCode,
+			Field:     lf.Field,
+			Operation: lf.Operation,
+			Err:       fmt.Errorf("filter must define only one of Operation, %s, %s or %s", filterAnd, filterOr, filterNot),
+		}
+	}
+
+	switch {
+	case lf.And != nil, lf.Or != nil:
+		filters, name := lf.And, filterAnd
+		if lf.Or != nil {
+			filters, name = lf.Or, filterOr
+		}
+		if len(filters) == 0 {
+			return nil, &FilterError{Code: InvalidFilterCode, Err: fmt.Errorf("filter %s must contain at least one filter", name)}
+		}
+		children := make([]Comparator, len(filters))
+		for i, child := range filters {
+			op, err := newFilterComparator(child, leaves)
+			if err != nil {
+				return nil, err
+			}
+			children[i] = op
+		}
+		if name == filterAnd {
+			return AndComparator{Children: children}, nil
+		}
+		return OrComparator{Children: children}, nil
+	case lf.Not != nil:
+		child, err := newFilterComparator(lf.Not, leaves)
+		if err != nil {
+			return nil, err
+		}
+		return NotComparator{Child: child}, nil
+	}
 
 	if lf.Operation == "" {
-		return nil, errors.New("filter Operation is undefined")
+		return nil, &FilterError{Code: InvalidFilterCode, Field: lf.Field, Err: errors.New("filter Operation is undefined")}
 	}
 
 	newOp, ok := ListOperations[lf.Operation]
 	if !ok {
-		return nil, fmt.Errorf("unknown filter operator %q", lf.Operation)
+		if _, ok := ListTransforms[lf.Operation]; ok {
+			return nil, &FilterError{
+				Code:      InvalidFilterCode,
+				Field:     lf.Field,
+				Operation: lf.Operation,
+				Err:       fmt.Errorf("filter operator %q works on the whole list, it can only be used at the top level of a filter or within a top level And", lf.Operation),
+			}
+		}
+		return nil, &FilterError{
+			Code:      UnknownOperationCode,
+			Field:     lf.Field,
+			Operation: lf.Operation,
+			Err:       fmt.Errorf("unknown filter operator %q", lf.Operation),
+		}
 	}
 	op, err := newOp(lf.Argument)
 	if err != nil {
-		return nil, err
+		return nil, &FilterError{Code: InvalidArgumentCode, Field: lf.Field, Operation: lf.Operation, Err: err}
 	}
 
-	return &listFilter{fieldName: lf.Field, op: op, json: lf}, nil
+	leaf := fieldComparator{fieldName: lf.Field, operation: lf.Operation, op: op}
+	*leaves = append(*leaves, leaf)
+	return leaf, nil
 }
 
-func (lf listFilter) match(raw interface{}) (bool, error) {
-	// In the case  the fieldName is empty, DeepExtractField will always error.
-	// So check for this and don't extract the field but just try to match it to nil.
-	// This comes into play with filters such as limitLength
-	if lf.fieldName == "" {
-		return lf.op.Match(nil), nil
+// apply filters the list with the comparator then runs each of the transforms in turn returning the resulting list.
+func (lf listFilter) apply(list []interface{}) ([]interface{}, error) {
+	if lf.op != nil {
+		var filtered []interface{}
+		for _, item := range list {
+			matched, err := lf.match(item)
+			if err != nil {
+				return nil, err
+			}
+			if matched {
+				filtered = append(filtered, item)
+			}
+		}
+		list = filtered
+	}
+
+	for _, t := range lf.transforms {
+		var err error
+		list, err = t.transform.Transform(list)
+		if err != nil {
+			var fe *FilterError
+			if errors.As(err, &fe) {
+				return nil, err
+			}
+			return nil, &FilterError{Code: OperationFailedCode, Operation: t.operation, Err: err}
+		}
 	}
-	field, err := deepExtractFieldWithError(raw, lf.fieldName)
+	return list, nil
+}
+
+func (lf listFilter) match(raw interface{}) (bool, error) {
+	values, err := lf.fieldValues(raw)
 	if err != nil {
 		return false, err
 	}
-	return lf.op.Match(field), nil
+	return lf.op.Match(values), nil
+}
+
+// fieldValues extracts the values of all fields referenced by the filter from a list item and checks them with any
+// TypeChecker comparators. An empty field name refers to the list item itself.
+func (lf listFilter) fieldValues(raw interface{}) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(lf.fieldNames))
+	for _, name := range lf.fieldNames {
+		if name == "" {
+			values[name] = raw
+			continue
+		}
+		field, found, err := lf.paths[name].lookup(raw)
+		if err != nil && (lf.required[name] || !isDocument(raw)) {
+			return nil, &FilterError{Code: FieldNotFoundCode, Field: name, Err: err}
+		}
+		if found {
+			values[name] = field
+		}
+	}
+	for _, leaf := range lf.checks {
+		if err := leaf.op.(TypeChecker).CheckType(values[leaf.fieldName]); err != nil {
+			fe := &FilterError{Code: TypeMismatchCode, Field: leaf.fieldName, Operation: leaf.operation}
+			if leaf.fieldName == "" {
+				fe.Err = fmt.Errorf("filter on the list items can never match: %v", err)
+			} else {
+				fe.Err = fmt.Errorf("filter on field %q can never match: %v", leaf.fieldName, err)
+			}
+			return nil, fe
+		}
+	}
+	return values, nil
+}
+
+// transformArgument returns the argument for a ListTransform stage of a filter. The Field of the stage is added to the
+// argument with the key "Field" so it isn't dropped, giving it both ways is an error.
+func transformArgument(stage *listFilterJSON) (map[string]interface{}, error) {
+	if stage.Field == "" {
+		return stage.Argument, nil
+	}
+	if _, ok := stage.Argument["Field"]; ok {
+		return nil, &FilterError{
+			Code:      InvalidFilterCode,
+			Field:     stage.Field,
+			Operation: stage.Operation,
+			Err:       errors.New("the field of a transform can be given as 'Field' or within 'Argument' but not both"),
+		}
+	}
+	arg := make(map[string]interface{}, len(stage.Argument)+1)
+	for k, v := range stage.Argument {
+		arg[k] = v
+	}
+	arg["Field"] = stage.Field
+	return arg, nil
+}
+
+// namedTransform is a ListTransform along with the name of its operation.
+type namedTransform struct {
+	operation string
+	transform ListTransform
+}
+
+// fieldComparator matches the Comparator for a single filter operation against the value of its field, the value
+// matched by a fieldComparator is the map of field values extracted by listFilter.match. A field which is not in the
+// map could not be found.
+type fieldComparator struct {
+	fieldName string
+	operation string
+	op        Comparator
+}
+
+func (c fieldComparator) Match(raw interface{}) bool {
+	values, _ := raw.(map[string]interface{})
+	value, exists := values[c.fieldName]
+	if pc, ok := c.op.(PresenceComparator); ok {
+		return pc.MatchPresence(value, exists)
+	}
+	return c.op.Match(value)
 }
@@ -3,9 +3,12 @@ package gql
 import (
 	"context"
 	"encoding/json"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/GannettDigital/graphql"
+	"github.com/GannettDigital/graphql/language/ast"
 )
 
 // TestResolveListField covers ResolveListField but also proper parsing of a filter in newListFilter.
@@ -14,9 +17,17 @@ import (
 func TestResolveListField(t *testing.T) {
 	s := testSchema(t)
 
+	byName := []*sortParameters{{field: "name"}}
+	var sortedByName []interface{}
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		sortedByName = append(sortedByName, map[string]interface{}{"name": name})
+	}
+	nameCursor := func(i int) string { return newListCursors(sortedByName, byName, cursorQuery(nil, byName)).cursor(i) }
+
 	tests := []struct {
 		description string
 		query       string
+		variables   map[string]interface{}
 		want        string
 		wantErr     bool
 		wantLF      *ListFunctions
@@ -47,6 +58,60 @@ func TestResolveListField(t *testing.T) {
 			query:       `query { q(id: "bad-total-count"){ totalIntlist }}`,
 			want:        `{"data":{"q":{"totalIntlist":0}}}`,
 		},
+		{
+			description: "Filtered count",
+			query:       `query { q(id: "1"){ totalItems filteredTotalItems(filter: {Field: "value", Operation: ">", Argument: {Value: 2}}) }}`,
+			want:        `{"data":{"q":{"filteredTotalItems":3,"totalItems":5}}}`,
+		},
+		{
+			description: "Filtered count without a filter",
+			query:       `query { q(id: "1"){ filteredTotalStringlist }}`,
+			want:        `{"data":{"q":{"filteredTotalStringlist":4}}}`,
+		},
+		{
+			description: "Filtered count missing value is 0 count",
+			query:       `query { q(id: "bad-total-count"){ filteredTotalIntlist(filter: {Operation: "==", Argument: {Value: 1}}) }}`,
+			want:        `{"data":{"q":{"filteredTotalIntlist":0}}}`,
+		},
+		{
+			description: "Filtered count error value is not a list",
+			query:       `query { q(id: "bad-total-count"){ filteredTotalItems }}`,
+			want:        `{"data":{"q":{"filteredTotalItems":null}},"errors":[{"message":"field value is not a valid list in the data","locations":[{"line":1,"column":35}]}]}`,
+			wantErr:     true,
+		},
+		{
+			description: "Filtered count with an invalid filter",
+			query:       `query { q(id: "1"){ filteredTotalItems(filter: {Field: "name", Operation: "==", Argument: {Value: 1}}) }}`,
+			wantErr:     true,
+		},
+		{
+			description: "reporting filtered count",
+			query:       `query { q(id: "1"){ filteredTotalItems(filter: {Field: "name", Operation: "==", Argument: {Value: "a"}}) }}`,
+			want:        `{"data":{"q":{"filteredTotalItems":1}}}`,
+			wantLF: &ListFunctions{
+				Filter: "Field:name, Operation:==, Arguments:a",
+			},
+		},
+		{
+			description: "Groups",
+			query:       `query { q(id: "1"){ itemsGroups(groupBy: "priority") { key count items { name } } }}`,
+			want:        `{"data":{"q":{"itemsGroups":[{"count":3,"items":[{"name":"a"},{"name":"d"},{"name":"e"}],"key":1},{"count":2,"items":[{"name":"c"},{"name":"b"}],"key":2}]}}}`,
+		},
+		{
+			description: "Groups with filter and sort",
+			query:       `query { q(id: "1"){ itemsGroups(groupBy: "priority", filter: {Field: "name", Operation: "!=", Argument: {Value: "a"}}, sort: {Field: "name", Order: "DESC"}) { key items { name } } }}`,
+			want:        `{"data":{"q":{"itemsGroups":[{"items":[{"name":"e"},{"name":"d"}],"key":1},{"items":[{"name":"c"},{"name":"b"}],"key":2}]}}}`,
+		},
+		{
+			description: "Groups of nested string field with null keys",
+			query:       `query { q(id: "1"){ itemsGroups(groupBy: "parent_name") { key count } }}`,
+			want:        `{"data":{"q":{"itemsGroups":[{"count":3,"key":null},{"count":1,"key":""},{"count":1,"key":"parentA"}]}}}`,
+		},
+		{
+			description: "Groups error on unsupported key",
+			query:       `query { q(id: "1"){ itemsGroups(groupBy: "leaf") { key } }}`,
+			wantErr:     true,
+		},
 		{
 			description: "Total items count with count unaffected by filter",
 			query:       `query { q(id: "1"){ totalItems items(filter: {Operation: "LIMIT", Argument: {Value: 2}}){name value} }}`,
@@ -88,7 +153,7 @@ func TestResolveListField(t *testing.T) {
 			wantErr:     true,
 		},
 		{
-			description: "invalid filter, object within the Argument object, currently unsupported",
+			description: "invalid filter, object within the Argument object, unsupported by ==",
 			query:       `query { q(id: "1"){ items(filter: {Field: "name", Operation: "==", Argument: {Value: {SubValue: "a"}}}){name value}}}`,
 			wantErr:     true,
 		},
@@ -112,11 +177,189 @@ func TestResolveListField(t *testing.T) {
 			query:       `query { q(id: "1"){ items(filter: {Field: "value", Operation: "==", Argument: {Value: 1}}){name value}}}`,
 			want:        `{"data":{"q":{"items":[{"name":"a","value":1}]}}}`,
 		},
+		{
+			description: "int equal filter, int64 field",
+			query:       `query { q(id: "1"){ items(filter: {Field: "value64", Operation: "==", Argument: {Value: 55}}){name value}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"e","value":5}]}}}`,
+		},
+		{
+			description: "int IN filter, int64 field",
+			query:       `query { q(id: "1"){ items(filter: {Field: "value64", Operation: "IN", Argument: {Values: [1, 55]}}){name value}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"e","value":5}]}}}`,
+		},
+		{
+			description: "int NOT IN filter",
+			query:       `query { q(id: "1"){ items(filter: {Field: "value", Operation: "NOT IN", Argument: {Values: [1, 2, 3]}}){name value}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"d","value":4},{"name":"e","value":5}]}}}`,
+		},
+		{
+			description: "invalid filter, int value can never match string field",
+			query:       `query { q(id: "1"){ items(filter: {Field: "name", Operation: "==", Argument: {Value: 1}}){name value}}}`,
+			wantErr:     true,
+		},
+		{
+			description: "invalid filter, string values can never match int field",
+			query:       `query { q(id: "1"){ items(filter: {Field: "value", Operation: "IN", Argument: {Values: ["1"]}}){name value}}}`,
+			wantErr:     true,
+		},
+		{
+			description: "numeric comparison with a string field matches no items",
+			query:       `query { q(id: "1"){ items(filter: {Field: "name", Operation: ">", Argument: {Value: 1}}){name value}}}`,
+			want:        `{"data":{"q":{"items":[]}}}`,
+		},
+		{
+			description: "invalid filter, value can never match within a group",
+			query:       `query { q(id: "1"){ items(filter: {Or: [{Field: "name", Operation: "==", Argument: {Value: "a"}}, {Field: "value", Operation: ">", Argument: {Value: 1}}, {Field: "leaf", Operation: "==", Argument: {Value: "a"}}]}){name value}}}`,
+			wantErr:     true,
+		},
+		{
+			description: "IS NULL filter, nil pointer and empty list",
+			query:       `query { q(id: "1"){ items(filter: {And: [{Field: "parent", Operation: "IS NULL"}, {Field: "tags", Operation: "IS NULL"}]}){name}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"c"},{"name":"d"},{"name":"e"}]}}}`,
+		},
+		{
+			description: "IS NOT NULL filter, nested field with nil parent",
+			query:       `query { q(id: "1"){ items(filter: {Field: "parent_name", Operation: "IS NOT NULL"}){name}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"a"},{"name":"b"}]}}}`,
+		},
+		{
+			description: "EXISTS filter, nested field",
+			query:       `query { q(id: "1"){ items(filter: {Field: "parent_name", Operation: "EXISTS"}){name}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"a"},{"name":"b"}]}}}`,
+		},
+		{
+			description: "invalid EXISTS filter, first level field not found",
+			query:       `query { q(id: "1"){ items(filter: {Field: "bogus", Operation: "EXISTS"}){name}}}`,
+			wantErr:     true,
+		},
+		{
+			description: "invalid IS NULL filter, first level field not found",
+			query:       `query { q(id: "1"){ items(filter: {Not: {Field: "bogus", Operation: "IS NULL"}}){name}}}`,
+			wantErr:     true,
+		},
+		{
+			description: "invalid filter, first level field not found",
+			query:       `query { q(id: "1"){ items(filter: {Or: [{Field: "bogus", Operation: "EXISTS"}, {Field: "bogus", Operation: "==", Argument: {Value: 1}}]}){name}}}`,
+			wantErr:     true,
+		},
+		{
+			description: "ANY filter on nested list of objects",
+			query:       `query { q(id: "1"){ items(filter: {Field: "children", Operation: "ANY", Argument: {Filter: {Field: "name", Operation: "==", Argument: {Value: "y"}}}}){name}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"c"},{"name":"a"}]}}}`,
+		},
+		{
+			description: "ALL filter on nested list of objects",
+			query:       `query { q(id: "1"){ items(filter: {Field: "children", Operation: "ALL", Argument: {Filter: {Field: "name", Operation: "==", Argument: {Value: "y"}}}}){name}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"c"},{"name":"d"},{"name":"b"},{"name":"e"}]}}}`,
+		},
+		{
+			description: "NONE filter on nested list of strings",
+			query:       `query { q(id: "1"){ items(filter: {Field: "tags", Operation: "NONE", Argument: {Filter: {Operation: "==", Argument: {Value: "x"}}}}){name}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"c"},{"name":"d"},{"name":"b"},{"name":"e"}]}}}`,
+		},
+		{
+			description: "LENGTH filter on nested list",
+			query:       `query { q(id: "1"){ items(filter: {Field: "children", Operation: "LENGTH", Argument: {Operation: ">", Value: 0}}){name}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"c"},{"name":"a"}]}}}`,
+		},
+		{
+			description: "ANY filter as a variable",
+			query:       `query($f: ListFilter) { q(id: "1"){ items(filter: $f){name}}}`,
+			variables:   map[string]interface{}{"f": map[string]interface{}{"Field": "children", "Operation": "ANY", "Argument": map[string]interface{}{"Filter": map[string]interface{}{"Field": "name", "Operation": "==", "Argument": map[string]interface{}{"Value": "x"}}}}},
+			want:        `{"data":{"q":{"items":[{"name":"a"}]}}}`,
+		},
+		{
+			description: "invalid filter, ANY nested field not found",
+			query:       `query { q(id: "1"){ items(filter: {Field: "children", Operation: "ANY", Argument: {Filter: {Field: "bogus", Operation: "==", Argument: {Value: "y"}}}}){name}}}`,
+			wantErr:     true,
+		},
+		{
+			description: "invalid filter, ANY on a field which is not a list",
+			query:       `query { q(id: "1"){ items(filter: {Field: "name", Operation: "ANY", Argument: {Filter: {Operation: "==", Argument: {Value: "y"}}}}){name}}}`,
+			wantErr:     true,
+		},
+		{
+			description: "string equal filter on the list itself",
+			query:       `query { q(id: "1"){ stringlist(filter: {Operation: "IN", Argument: {Values: ["b", "d"]}})}}`,
+			want:        `{"data":{"q":{"stringlist":["b","d"]}}}`,
+		},
 		{
 			description: "limitLength filter",
 			query:       `query { q(id: "1"){ items(filter: {Operation: "LIMIT", Argument: {Value: 2}}){name value}}}`,
 			want:        `{"data":{"q":{"items":[{"name":"c","value":3},{"name":"a","value":1}]}}}`,
 		},
+		{
+			description: "filter followed by OFFSET and LIMIT",
+			query:       `query { q(id: "1"){ items(filter: {And: [{Field: "value", Operation: ">", Argument: {Value: 1}}, {Operation: "OFFSET", Argument: {Value: 1}}, {Operation: "LIMIT", Argument: {Value: 2}}]}){name}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"d"},{"name":"b"}]}}}`,
+		},
+		{
+			description: "REVERSE filter",
+			query:       `query { q(id: "1"){ stringlist(filter: {Operation: "REVERSE"})}}`,
+			want:        `{"data":{"q":{"stringlist":["d","c","b","a"]}}}`,
+		},
+		{
+			description: "DISTINCT filter by field",
+			query:       `query { q(id: "1"){ items(filter: {Operation: "DISTINCT", Argument: {Field: "priority"}}){name}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"c"},{"name":"a"}]}}}`,
+		},
+		{
+			description: "DISTINCT filter with a top level field",
+			query:       `query { q(id: "1"){ items(filter: {Field: "priority", Operation: "DISTINCT"}){name}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"c"},{"name":"a"}]}}}`,
+		},
+		{
+			description: "invalid filter, DISTINCT with a top level field and argument field",
+			query:       `query { q(id: "1"){ items(filter: {Field: "priority", Operation: "DISTINCT", Argument: {Field: "name"}}){name}}}`,
+			wantErr:     true,
+		},
+		{
+			description: "invalid filter, LIMIT within Or",
+			query:       `query { q(id: "1"){ items(filter: {Or: [{Field: "name", Operation: "==", Argument: {Value: "a"}}, {Operation: "LIMIT", Argument: {Value: 1}}]}){name}}}`,
+			wantErr:     true,
+		},
+		{
+			description: "invalid filter, LIMIT within ANY",
+			query:       `query { q(id: "1"){ items(filter: {Field: "children", Operation: "ANY", Argument: {Filter: {Operation: "LIMIT", Argument: {Value: 1}}}}){name}}}`,
+			wantErr:     true,
+		},
+		{
+			description: "offset and limit after sort and filter",
+			query:       `query { q(id: "1"){ items(filter: {Field: "value", Operation: ">", Argument: {Value: 1}}, sort: {Field: "name"}, offset: 1, limit: 2){name}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"c"},{"name":"d"}]}}}`,
+		},
+		{
+			description: "first without after",
+			query:       `query { q(id: "1"){ items(sort: {Field: "name"}, first: 2){name}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"a"},{"name":"b"}]}}}`,
+		},
+		{
+			description: "first and end cursor",
+			query:       `query { q(id: "1"){ items(sort: {Field: "name"}, first: 2){name} itemsEndCursor(sort: {Field: "name"}, first: 2)}}`,
+			want:        `{"data":{"q":{"items":[{"name":"a"},{"name":"b"}],"itemsEndCursor":"` + nameCursor(1) + `"}}}`,
+		},
+		{
+			description: "first and after",
+			query:       `query($c: String) { q(id: "1"){ items(sort: {Field: "name"}, first: 2, after: $c){name} itemsEndCursor(sort: {Field: "name"}, first: 2, after: $c)}}`,
+			variables:   map[string]interface{}{"c": nameCursor(1)},
+			want:        `{"data":{"q":{"items":[{"name":"c"},{"name":"d"}],"itemsEndCursor":"` + nameCursor(3) + `"}}}`,
+		},
+		{
+			description: "end cursor of an empty page",
+			query:       `query { q(id: "1"){ itemsEndCursor(filter: {Field: "value", Operation: ">", Argument: {Value: 10}})}}`,
+			want:        `{"data":{"q":{"itemsEndCursor":null}}}`,
+		},
+		{
+			description: "invalid paging, after cursor from a different sort",
+			query:       `query($c: String) { q(id: "1"){ items(sort: {Field: "name", Order: "DESC"}, first: 2, after: $c){name}}}`,
+			variables:   map[string]interface{}{"c": nameCursor(1)},
+			wantErr:     true,
+		},
+		{
+			description: "invalid paging, offset with first",
+			query:       `query { q(id: "1"){ items(offset: 1, first: 2){name}}}`,
+			wantErr:     true,
+		},
 		{
 			description: "NOT IN filter",
 			query:       `query { q(id: "1"){ items(filter: {Field: "name", Operation: "NOT IN", Argument: {Values: ["c", "d"]}}){name value}}}`,
@@ -127,6 +370,77 @@ func TestResolveListField(t *testing.T) {
 			query:       `query { q(id: "1"){ items(filter: {Field: "floatvalue", Operation: "<", Argument: {Value: 2}}){name value floatvalue}}}`,
 			want:        `{"data":{"q":{"items":[{"floatvalue":1.1,"name":"a","value":1},{"floatvalue":1.2,"name":"b","value":2}]}}}`,
 		},
+		{
+			description: "float > filter, float value",
+			query:       `query { q(id: "1"){ items(filter: {Field: "floatvalue", Operation: ">", Argument: {Value: 2.15}}){name floatvalue}}}`,
+			want:        `{"data":{"q":{"items":[{"floatvalue":2.2,"name":"d"},{"floatvalue":5.5,"name":"e"}]}}}`,
+		},
+		{
+			description: "float > filter, float64 field but int value is not truncated",
+			query:       `query { q(id: "1"){ items(filter: {Field: "floatvalue", Operation: ">", Argument: {Value: 2}}){name floatvalue}}}`,
+			want:        `{"data":{"q":{"items":[{"floatvalue":2.1,"name":"c"},{"floatvalue":2.2,"name":"d"},{"floatvalue":5.5,"name":"e"}]}}}`,
+		},
+		{
+			description: "float equal filter",
+			query:       `query { q(id: "1"){ items(filter: {Field: "floatvalue", Operation: "==", Argument: {Value: 1.2}}){name floatvalue}}}`,
+			want:        `{"data":{"q":{"items":[{"floatvalue":1.2,"name":"b"}]}}}`,
+		},
+		{
+			description: "float <= filter, int field",
+			query:       `query { q(id: "1"){ items(filter: {Field: "value", Operation: "<=", Argument: {Value: 2.5}}){name value}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"a","value":1},{"name":"b","value":2}]}}}`,
+		},
+		{
+			description: "float > filter from variable",
+			query:       `query($f: ListFilter) { q(id: "1"){ items(filter: $f){name floatvalue}}}`,
+			variables:   map[string]interface{}{"f": map[string]interface{}{"Field": "floatvalue", "Operation": ">", "Argument": map[string]interface{}{"Value": 2.15}}},
+			want:        `{"data":{"q":{"items":[{"floatvalue":2.2,"name":"d"},{"floatvalue":5.5,"name":"e"}]}}}`,
+		},
+		{
+			description: "string STARTS WITH filter",
+			query:       `query { q(id: "1"){ items(filter: {Field: "leaf_name", Operation: "STARTS WITH", Argument: {Value: "leaf"}}){name}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"a"},{"name":"b"}]}}}`,
+		},
+		{
+			description: "string ENDS WITH IGNORE CASE filter",
+			query:       `query { q(id: "1"){ items(filter: {Field: "leaf_name", Operation: "ENDS WITH IGNORE CASE", Argument: {Value: "fb"}}){name}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"b"}]}}}`,
+		},
+		{
+			description: "string MATCHES filter",
+			query:       `query { q(id: "1"){ items(filter: {Field: "name", Operation: "MATCHES", Argument: {Value: "^[a-c]$"}}){name}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"c"},{"name":"a"},{"name":"b"}]}}}`,
+		},
+		{
+			description: "invalid filter, MATCHES invalid expression",
+			query:       `query { q(id: "1"){ items(filter: {Field: "name", Operation: "MATCHES", Argument: {Value: "[a-c"}}){name}}}`,
+			wantErr:     true,
+		},
+		{
+			description: "time BEFORE filter",
+			query:       `query { q(id: "1"){ items(filter: {Field: "published", Operation: "BEFORE", Argument: {Value: "2019-10-02T00:00:00Z"}}){name}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"a"}]}}}`,
+		},
+		{
+			description: "time BETWEEN filter",
+			query:       `query { q(id: "1"){ items(filter: {Field: "published", Operation: "BETWEEN", Argument: {From: "2019-10-01T00:00:00Z", To: "2019-10-02T00:00:00Z"}}){name}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"a"},{"name":"b"}]}}}`,
+		},
+		{
+			description: "time BETWEEN Within filter",
+			query:       `query { q(id: "1"){ items(filter: {Field: "published", Operation: "BETWEEN", Argument: {Within: "24h"}}){name}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"c"},{"name":"e"}]}}}`,
+		},
+		{
+			description: "time AFTER filter and sort",
+			query:       `query { q(id: "1"){ items(filter: {Field: "published", Operation: "AFTER", Argument: {Value: "2019-10-01T00:00:00Z"}}, sort: {Field: "name"}){name}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"b"},{"name":"c"},{"name":"d"},{"name":"e"}]}}}`,
+		},
+		{
+			description: "invalid filter, time not RFC 3339",
+			query:       `query { q(id: "1"){ items(filter: {Field: "published", Operation: "AFTER", Argument: {Value: "yesterday"}}){name}}}`,
+			wantErr:     true,
+		},
 		{
 			description: "2nd level, string equal filter",
 			query:       `query { q(id: "1"){ items(filter: {Field: "leaf_name", Operation: "==", Argument: {Value: "leafA"}}){name value leaf{ name }}}}`,
@@ -155,6 +469,133 @@ func TestResolveListField(t *testing.T) {
 				Filter: "Field:leaf_falsename, Operation:==, Arguments:leaf",
 			},
 		},
+		{
+			description: "And filter",
+			query:       `query { q(id: "1"){ items(filter: {And: [{Field: "value", Operation: ">", Argument: {Value: 1}}, {Field: "value", Operation: "<", Argument: {Value: 4}}]}){name value}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"c","value":3},{"name":"b","value":2}]}}}`,
+		},
+		{
+			description: "Or filter",
+			query:       `query { q(id: "1"){ items(filter: {Or: [{Field: "name", Operation: "==", Argument: {Value: "a"}}, {Field: "value", Operation: ">=", Argument: {Value: 4}}]}){name value}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"a","value":1},{"name":"d","value":4},{"name":"e","value":5}]}}}`,
+		},
+		{
+			description: "Not filter",
+			query:       `query { q(id: "1"){ items(filter: {Not: {Field: "name", Operation: "IN", Argument: {Values: ["a", "b", "c"]}}}){name value}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"d","value":4},{"name":"e","value":5}]}}}`,
+		},
+		{
+			description: "nested And, Or and Not filter",
+			query:       `query { q(id: "1"){ items(filter: {And: [{Not: {Field: "name", Operation: "==", Argument: {Value: "e"}}}, {Or: [{Field: "value", Operation: "<=", Argument: {Value: 2}}, {Field: "leaf_name", Operation: "==", Argument: {Value: "leafB"}}, {Field: "floatvalue", Operation: ">=", Argument: {Value: 2}}]}]}){name value}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"c","value":3},{"name":"a","value":1},{"name":"d","value":4},{"name":"b","value":2}]}}}`,
+		},
+		{
+			description: "nested filter from variable",
+			query:       `query($f: ListFilter) { q(id: "1"){ items(filter: $f){name value}}}`,
+			variables: map[string]interface{}{"f": map[string]interface{}{"Or": []interface{}{
+				map[string]interface{}{"Field": "value", "Operation": "==", "Argument": map[string]interface{}{"Value": float64(5)}},
+				map[string]interface{}{"Not": map[string]interface{}{"Field": "value", "Operation": ">", "Argument": map[string]interface{}{"Value": float64(1)}}},
+			}}},
+			want: `{"data":{"q":{"items":[{"name":"a","value":1},{"name":"e","value":5}]}}}`,
+		},
+		{
+			description: "invalid filter, And not a list",
+			query:       `query { q(id: "1"){ items(filter: {And: {Field: "name", Operation: "==", Argument: {Value: "a"}}}){name value}}}`,
+			wantErr:     true,
+		},
+		{
+			description: "invalid filter, Or item not an object",
+			query:       `query { q(id: "1"){ items(filter: {Or: ["a"]}){name value}}}`,
+			wantErr:     true,
+		},
+		{
+			description: "invalid filter, empty And",
+			query:       `query { q(id: "1"){ items(filter: {And: []}){name value}}}`,
+			wantErr:     true,
+		},
+		{
+			description: "invalid filter, Not not an object",
+			query:       `query { q(id: "1"){ items(filter: {Not: ["a"]}){name value}}}`,
+			wantErr:     true,
+		},
+		{
+			description: "invalid filter, Operation and And both defined",
+			query:       `query { q(id: "1"){ items(filter: {Operation: "LIMIT", Argument: {Value: 1}, And: [{Field: "name", Operation: "==", Argument: {Value: "a"}}]}){name value}}}`,
+			wantErr:     true,
+		},
+		{
+			description: "invalid filter, And and Or both defined",
+			query:       `query { q(id: "1"){ items(filter: {And: [{Field: "name", Operation: "==", Argument: {Value: "a"}}], Or: [{Field: "name", Operation: "==", Argument: {Value: "b"}}]}){name value}}}`,
+			wantErr:     true,
+		},
+		{
+			description: "invalid filter, unknown operation within a group",
+			query:       `query { q(id: "1"){ items(filter: {Or: [{Field: "name", Operation: "==", Argument: {Value: "a"}}, {Field: "name", Operation: "unknown"}]}){name value}}}`,
+			wantErr:     true,
+		},
+		{
+			description: "invalid filter, field not found within a group",
+			query:       `query { q(id: "1"){ items(filter: {Or: [{Field: "name", Operation: "==", Argument: {Value: "c"}}, {Field: "notaname", Operation: "==", Argument: {Value: "a"}}]}){name value}}}`,
+			wantErr:     true,
+		},
+		{
+			description: "reporting nested filter",
+			query:       `query { q(id: "1"){ items(filter: {And: [{Field: "name", Operation: "==", Argument: {Value: "a"}}, {Not: {Field: "value", Operation: "==", Argument: {Value: 2}}}]}){name value}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"a","value":1}]}}}`,
+			wantLF: &ListFunctions{
+				Filter: "(Field:name, Operation:==, Arguments:a) AND (NOT (Field:value, Operation:==, Arguments:2))",
+			},
+		},
+		{
+			description: "string equal filter from variable",
+			query:       `query($f: ListFilter) { q(id: "1"){ items(filter: $f){name value}}}`,
+			variables:   map[string]interface{}{"f": map[string]interface{}{"Field": "name", "Operation": "==", "Argument": map[string]interface{}{"Value": "a"}}},
+			want:        `{"data":{"q":{"items":[{"name":"a","value":1}]}}}`,
+		},
+		{
+			description: "int equal filter from variable, JSON decoded number",
+			query:       `query($f: ListFilter) { q(id: "1"){ items(filter: $f){name value}}}`,
+			variables:   map[string]interface{}{"f": map[string]interface{}{"Field": "value", "Operation": "==", "Argument": map[string]interface{}{"Value": float64(1)}}},
+			want:        `{"data":{"q":{"items":[{"name":"a","value":1}]}}}`,
+		},
+		{
+			description: "NOT IN filter from variable",
+			query:       `query($f: ListFilter) { q(id: "1"){ items(filter: $f){name value}}}`,
+			variables:   map[string]interface{}{"f": map[string]interface{}{"Field": "name", "Operation": "NOT IN", "Argument": map[string]interface{}{"Values": []interface{}{"c", "d"}}}},
+			want:        `{"data":{"q":{"items":[{"name":"a","value":1},{"name":"b","value":2},{"name":"e","value":5}]}}}`,
+		},
+		{
+			description: "limitLength filter from variable",
+			query:       `query($f: ListFilter) { q(id: "1"){ items(filter: $f){name value}}}`,
+			variables:   map[string]interface{}{"f": map[string]interface{}{"Operation": "LIMIT", "Argument": map[string]interface{}{"Value": 2}}},
+			want:        `{"data":{"q":{"items":[{"name":"c","value":3},{"name":"a","value":1}]}}}`,
+		},
+		{
+			description: "unset filter variable",
+			query:       `query($f: ListFilter) { q(id: "1"){ items(filter: $f){name value}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"c","value":3},{"name":"a","value":1},{"name":"d","value":4},{"name":"b","value":2},{"name":"e","value":5}]}}}`,
+		},
+		{
+			description: "invalid filter from variable, field not a string",
+			query:       `query($f: ListFilter) { q(id: "1"){ items(filter: $f){name value}}}`,
+			variables:   map[string]interface{}{"f": map[string]interface{}{"Field": 2, "Operation": "==", "Argument": map[string]interface{}{"Value": "a"}}},
+			wantErr:     true,
+		},
+		{
+			description: "invalid filter from variable, unknown operation",
+			query:       `query($f: ListFilter) { q(id: "1"){ items(filter: $f){name value}}}`,
+			variables:   map[string]interface{}{"f": map[string]interface{}{"Field": "name", "Operation": "unknown", "Argument": map[string]interface{}{"Value": "a"}}},
+			wantErr:     true,
+		},
+		{
+			description: "reporting filter from variable",
+			query:       `query($f: ListFilter) { q(id: "1"){ items(filter: $f){name value}}}`,
+			variables:   map[string]interface{}{"f": map[string]interface{}{"Field": "leaf_falsename", "Operation": "==", "Argument": map[string]interface{}{"Value": "leaf"}}},
+			want:        `{"data":{"q":{"items":[]}}}`,
+			wantLF: &ListFunctions{
+				Filter: "Field:leaf_falsename, Operation:==, Arguments:leaf",
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -165,9 +606,10 @@ func TestResolveListField(t *testing.T) {
 		}
 
 		params := graphql.Params{
-			Context:       ctx,
-			Schema:        s,
-			RequestString: test.query,
+			Context:        ctx,
+			Schema:         s,
+			RequestString:  test.query,
+			VariableValues: test.variables,
 		}
 
 		resp := graphql.Do(params)
@@ -200,6 +642,120 @@ func TestResolveListField(t *testing.T) {
 	}
 }
 
+func TestParseASTValue(t *testing.T) {
+	name := func(value string) *ast.Name {
+		return ast.NewName(&ast.Name{Value: value})
+	}
+
+	tests := []struct {
+		description string
+		in          interface{}
+		want        interface{}
+		wantErr     bool
+	}{
+		{
+			description: "nil",
+			in:          nil,
+			want:        nil,
+		},
+		{
+			description: "not an AST value",
+			in:          "a",
+			wantErr:     true,
+		},
+		{
+			description: "string",
+			in:          ast.NewStringValue(&ast.StringValue{Value: "a"}),
+			want:        "a",
+		},
+		{
+			description: "int",
+			in:          ast.NewIntValue(&ast.IntValue{Value: "-3"}),
+			want:        -3,
+		},
+		{
+			description: "invalid int",
+			in:          ast.NewIntValue(&ast.IntValue{Value: "a"}),
+			wantErr:     true,
+		},
+		{
+			description: "float",
+			in:          ast.NewFloatValue(&ast.FloatValue{Value: "3.5"}),
+			want:        3.5,
+		},
+		{
+			description: "float with exponent",
+			in:          ast.NewFloatValue(&ast.FloatValue{Value: "1e3"}),
+			want:        1000.0,
+		},
+		{
+			description: "invalid float",
+			in:          ast.NewFloatValue(&ast.FloatValue{Value: "a"}),
+			wantErr:     true,
+		},
+		{
+			description: "boolean true",
+			in:          ast.NewBooleanValue(&ast.BooleanValue{Value: true}),
+			want:        true,
+		},
+		{
+			description: "boolean false",
+			in:          ast.NewBooleanValue(&ast.BooleanValue{Value: false}),
+			want:        false,
+		},
+		{
+			description: "enum",
+			in:          ast.NewEnumValue(&ast.EnumValue{Value: "ASC"}),
+			want:        "ASC",
+		},
+		{
+			description: "list of mixed values",
+			in: ast.NewListValue(&ast.ListValue{Values: []ast.Value{
+				ast.NewIntValue(&ast.IntValue{Value: "1"}),
+				ast.NewStringValue(&ast.StringValue{Value: "a"}),
+			}}),
+			want: []interface{}{1, "a"},
+		},
+		{
+			description: "nested object",
+			in: ast.NewObjectValue(&ast.ObjectValue{Fields: []*ast.ObjectField{
+				ast.NewObjectField(&ast.ObjectField{Name: name("a"), Value: ast.NewBooleanValue(&ast.BooleanValue{Value: true})}),
+				ast.NewObjectField(&ast.ObjectField{Name: name("b"), Value: ast.NewObjectValue(&ast.ObjectValue{Fields: []*ast.ObjectField{
+					ast.NewObjectField(&ast.ObjectField{Name: name("c"), Value: ast.NewFloatValue(&ast.FloatValue{Value: "0.5"})}),
+				}})}),
+				ast.NewObjectField(&ast.ObjectField{Name: name("d")}),
+			}}),
+			want: map[string]interface{}{"a": true, "b": map[string]interface{}{"c": 0.5}, "d": nil},
+		},
+		{
+			description: "nested object with invalid value",
+			in: ast.NewObjectValue(&ast.ObjectValue{Fields: []*ast.ObjectField{
+				ast.NewObjectField(&ast.ObjectField{Name: name("a"), Value: ast.NewIntValue(&ast.IntValue{Value: "a"})}),
+			}}),
+			wantErr: true,
+		},
+		{
+			description: "variable",
+			in:          ast.NewVariable(&ast.Variable{Name: name("v")}),
+			wantErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := parseASTValue(test.in)
+		switch {
+		case test.wantErr && err != nil:
+			continue
+		case test.wantErr && err == nil:
+			t.Errorf("Test %q - got nil, want error", test.description)
+		case !test.wantErr && err != nil:
+			t.Errorf("Test %q - got err, want nil: %v", test.description, err)
+		case !reflect.DeepEqual(got, test.want):
+			t.Errorf("Test %q - got %#v, want %#v", test.description, got, test.want)
+		}
+	}
+}
+
 func testSchema(t *testing.T) graphql.Schema {
 	type leaf struct {
 		Name string
@@ -209,7 +765,12 @@ func testSchema(t *testing.T) graphql.Schema {
 		Value      int
 		Value64    int64
 		FloatValue float64
+		Priority   int
+		Published  time.Time
 		Leaf       leaf
+		Parent     *leaf
+		Tags       []string
+		Children   []leaf
 	}
 	type testStruct struct {
 		Items      []item
@@ -219,12 +780,13 @@ func testSchema(t *testing.T) graphql.Schema {
 		FloatList  []float64
 	}
 
+	now := time.Now()
 	fullList := []item{
-		{Name: "c", Value: 3, FloatValue: 2.1},
-		{Name: "a", Value: 1, FloatValue: 1.1, Leaf: leaf{Name: "leafA"}},
-		{Name: "d", Value: 4, FloatValue: 2.2},
-		{Name: "b", Value: 2, FloatValue: 1.2, Leaf: leaf{Name: "leafB"}},
-		{Name: "e", Value: 5, Value64: 55, FloatValue: 5.5},
+		{Name: "c", Value: 3, FloatValue: 2.1, Priority: 2, Published: now.Add(-2 * time.Hour), Children: []leaf{{Name: "y"}}},
+		{Name: "a", Value: 1, FloatValue: 1.1, Priority: 1, Published: time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC), Leaf: leaf{Name: "leafA"}, Parent: &leaf{Name: "parentA"}, Tags: []string{"x"}, Children: []leaf{{Name: "x"}, {Name: "y"}}},
+		{Name: "d", Value: 4, FloatValue: 2.2, Priority: 1, Published: now.Add(-48 * time.Hour)},
+		{Name: "b", Value: 2, FloatValue: 1.2, Priority: 2, Published: time.Date(2019, 10, 2, 0, 0, 0, 0, time.UTC), Leaf: leaf{Name: "leafB"}, Parent: &leaf{}, Tags: []string{}},
+		{Name: "e", Value: 5, Value64: 55, FloatValue: 5.5, Priority: 1, Published: now.Add(-30 * time.Minute)},
 	}
 
 	testData := testStruct{
@@ -239,6 +801,8 @@ func testSchema(t *testing.T) graphql.Schema {
 	if err != nil {
 		t.Fatal(err)
 	}
+	ob.SetFilteredCountFields(true)
+	ob.SetGroupFields(true)
 
 	types := ob.BuildTypes()
 	queryCfg := graphql.ObjectConfig{
@@ -277,3 +841,23 @@ func testSchema(t *testing.T) graphql.Schema {
 	}
 	return s
 }
+
+func BenchmarkListFilter(b *testing.B) {
+	articles := benchmarkArticles(1000)
+	filter, err := newListFilter(&listFilterJSON{And: []*listFilterJSON{
+		{Field: "wordCount", Operation: ">", Argument: map[string]interface{}{"Value": 1000}},
+		{Field: "author_name", Operation: "STARTS WITH", Argument: map[string]interface{}{"Value": "author 1"}},
+		{Field: "type", Operation: "==", Argument: map[string]interface{}{"Value": "article"}},
+	}})
+	if err != nil {
+		b.Fatal(err)
+	}
+	list := make([]interface{}, len(articles))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		copy(list, articles)
+		if _, err := filter.apply(list); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
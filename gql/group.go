@@ -0,0 +1,182 @@
+package gql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GannettDigital/graphql"
+	"github.com/GannettDigital/graphql/language/ast"
+)
+
+const (
+	groupsSuffix        = "Groups"
+	groupByArgumentName = "groupBy"
+)
+
+var graphqlGroupKey = graphql.NewScalar(graphql.ScalarConfig{
+	Name:         "GroupKey",
+	Description:  "The key of a group of list items, either a string, number or boolean.",
+	Serialize:    serializeGroupKey,
+	ParseValue:   func(value interface{}) interface{} { return nil },
+	ParseLiteral: func(valueAST ast.Value) interface{} { return nil },
+})
+
+// listGroup is a single group resolved for a groups field, the key is a canonical value as returned by coerceValue or
+// nil for items where the field is null.
+type listGroup struct {
+	key   interface{}
+	items []interface{}
+}
+
+// SetGroupFields sets whether a field named '<name>Groups' is built next to every list field to group the list items by
+// a field, by default it is not. Each groups field has its own group object type named after the list field. This
+// must be called before BuildInterfaces or BuildTypes.
+func (ob *ObjectBuilder) SetGroupFields(enabled bool) {
+	ob.groups = enabled
+}
+
+// buildGroup creates the group object for a list field given the type of items in the list.
+// The group has the fields 'key', 'count' and 'items'.
+func (ob *ObjectBuilder) buildGroup(name, parent string, itemType graphql.Type) *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: strings.ToLower(fullFieldName(name, parent)) + "Group",
+		Fields: graphql.Fields{
+			"key": &graphql.Field{
+				Name:        "key",
+				Type:        graphqlGroupKey,
+				Description: "The value of the grouped field shared by all items in the group.",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*listGroup).key, nil
+				},
+			},
+			"count": &graphql.Field{
+				Name:        "count",
+				Type:        graphql.NewNonNull(graphql.Int),
+				Description: "The number of items in the group.",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return len(p.Source.(*listGroup).items), nil
+				},
+			},
+			"items": &graphql.Field{
+				Name:        "items",
+				Type:        graphql.NewNonNull(graphql.NewList(itemType)),
+				Description: "The items in the group in the same order as the sorted list.",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*listGroup).items, nil
+				},
+			},
+		},
+	})
+}
+
+// ResolveListGroups accepts a groups field name, a name of the list field, and a parent name. It returns the items of
+// the list grouped by the value of the field named in the 'groupBy' argument, which may include FieldPathSeparator.
+// The list is sorted and filtered just as with ResolveListField before grouping so the items within each group keep
+// the sorted order. Groups are ordered by key, a null key first then false before true, numbers and then strings.
+// Keys must be strings, numbers or booleans.
+// It will also report the queried field to the QueryReporter and the filter and sort to the QueryFunctionReporter if
+// found in the context.
+func ResolveListGroups(groupsFieldName, listFieldName, parent string) graphql.FieldResolveFn {
+	return collectListErrors(func(p graphql.ResolveParams) (interface{}, error) {
+		if qr, ok := p.Context.Value(QueryReporterContextKey).(QueryReporter); ok && qr != nil {
+			if err := qr.QueriedField(fullFieldName(groupsFieldName, parent)); err != nil {
+				return nil, err
+			}
+		}
+
+		args, err := parseListArguments(p, groupsFieldName, parent)
+		if err != nil {
+			return nil, err
+		}
+
+		items, err := filteredListItems(p, args, listFieldName, parent)
+		if err != nil {
+			return nil, err
+		}
+
+		field, _ := p.Args[groupByArgumentName].(string)
+		return groupItems(items, field)
+	})
+}
+
+// groupItems groups the items by the value of the field, an empty field groups by the items themselves.
+func groupItems(items []interface{}, field string) ([]*listGroup, error) {
+	var groups []*listGroup
+	index := make(map[interface{}]*listGroup)
+	path := compileFieldPath(field)
+	for _, item := range items {
+		raw := item
+		if field != "" {
+			var err error
+			raw, _, err = path.lookup(item)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		var key interface{}
+		if !isNil(raw) {
+			var ok bool
+			key, ok = distinctKey(raw)
+			if !ok {
+				return nil, fmt.Errorf("unable to group by a value of type %T, only strings, numbers and booleans can be grouped", raw)
+			}
+		}
+
+		group, ok := index[key]
+		if !ok {
+			group = &listGroup{key: key}
+			index[key] = group
+			groups = append(groups, group)
+		}
+		group.items = append(group.items, item)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return compareGroupKeys(groups[i].key, groups[j].key) < 0
+	})
+	return groups, nil
+}
+
+// compareGroupKeys orders group keys, a nil key first then booleans, numbers and strings.
+func compareGroupKeys(a, b interface{}) int {
+	rank := func(key interface{}) int {
+		switch valueCategory(key) {
+		case booleanCategory:
+			return 1
+		case numberCategory:
+			return 2
+		case stringCategory:
+			return 3
+		}
+		return 0
+	}
+	if c := rank(a) - rank(b); c != 0 {
+		return c
+	}
+
+	switch a := a.(type) {
+	case bool:
+		switch {
+		case !a && b.(bool):
+			return -1
+		case a && !b.(bool):
+			return 1
+		}
+	case string:
+		return strings.Compare(a, b.(string))
+	case int64, uint64, float64:
+		c, _ := compareNumbers(a, b)
+		return c
+	}
+	return 0
+}
+
+// serializeGroupKey is the graphql.SerializeFn for the GroupKey scalar.
+func serializeGroupKey(value interface{}) interface{} {
+	if c, ok := coerceValue(value); ok {
+		return c
+	}
+	return nil
+}
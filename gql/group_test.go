@@ -0,0 +1,73 @@
+package gql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGroupItems(t *testing.T) {
+	tests := []struct {
+		description string
+		items       []interface{}
+		field       string
+		want        []*listGroup
+		wantErr     bool
+	}{
+		{
+			description: "Booleans",
+			items:       []interface{}{true, false, true},
+			want: []*listGroup{
+				{key: false, items: []interface{}{false}},
+				{key: true, items: []interface{}{true, true}},
+			},
+		},
+		{
+			description: "Integers of different types",
+			items:       []interface{}{3, int64(1), 1.0, uint8(3)},
+			want: []*listGroup{
+				{key: int64(1), items: []interface{}{int64(1), 1.0}},
+				{key: int64(3), items: []interface{}{3, uint8(3)}},
+			},
+		},
+		{
+			description: "Mixed keys",
+			items:       []interface{}{"a", nil, 2, false},
+			want: []*listGroup{
+				{key: nil, items: []interface{}{nil}},
+				{key: false, items: []interface{}{false}},
+				{key: int64(2), items: []interface{}{2}},
+				{key: "a", items: []interface{}{"a"}},
+			},
+		},
+		{
+			description: "By field",
+			items:       []interface{}{TestBase{Id: "b"}, TestBase{Id: "a"}, TestBase{Id: "b"}},
+			field:       "id",
+			want: []*listGroup{
+				{key: "a", items: []interface{}{TestBase{Id: "a"}}},
+				{key: "b", items: []interface{}{TestBase{Id: "b"}, TestBase{Id: "b"}}},
+			},
+		},
+		{
+			description: "Field not found",
+			items:       []interface{}{TestBase{Id: "b"}},
+			field:       "bogus",
+			wantErr:     true,
+		},
+		{
+			description: "Unsupported key",
+			items:       []interface{}{[]int{1}},
+			wantErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := groupItems(test.items, test.field)
+		if (err != nil) != test.wantErr {
+			t.Errorf("Test %q - got err %v, want err %t", test.description, err, test.wantErr)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Test %q - got %v, want %v", test.description, got, test.want)
+		}
+	}
+}
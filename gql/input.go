@@ -0,0 +1,262 @@
+package gql
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/GannettDigital/graphql"
+	"github.com/GannettDigital/graphql/language/ast"
+)
+
+// operationEnumNames are the GraphQL enum value names for the operations which are not valid GraphQL names.
+// Other operations use their name with spaces replaced by underscores, ie 'NOT IN' becomes NOT_IN.
+var operationEnumNames = map[string]string{
+	"==": "EQ",
+	"!=": "NE",
+	">":  "GT",
+	">=": "GTE",
+	"<":  "LT",
+	"<=": "LTE",
+}
+
+// SetTypedListArguments sets whether the filter and sort arguments of list fields are built as GraphQL input objects
+// rather than the opaque ListFilter and SortFilter scalars, by default they are not. With typed arguments each list
+// has an enum of the paths of the fields in its items and a shared enum of the operations in ListOperations and
+// ListTransforms so that an invalid 'Field' or 'Operation' is rejected when the query is validated.
+// The operations which aren't valid GraphQL names are renamed, ie '==' is EQ and 'NOT IN' is NOT_IN, spaces in any
+// other operation are replaced by underscores and building the types panics if the result is still not a valid name.
+// This must be called before BuildInterfaces or BuildTypes.
+func (ob *ObjectBuilder) SetTypedListArguments(enabled bool) {
+	ob.typedArguments = enabled
+}
+
+// typedListFieldArguments returns the arguments of a list field just as listFieldArguments but with the filter and
+// sort built as input objects specific to the type of items in the list, paths are the fields of the items as
+// returned by fieldPaths. The type used for the field paths is also returned, it is an enum unless the items have no
+// fields in which case it is a String.
+func (ob *ObjectBuilder) typedListFieldArguments(name, parent string, paths []string) (graphql.FieldConfigArgument, graphql.Input) {
+	objectName := strings.ToLower(fullFieldName(name, parent))
+
+	var fieldType graphql.Input = graphql.String
+	if len(paths) > 0 {
+		values := graphql.EnumValueConfigMap{}
+		for _, path := range paths {
+			values[path] = &graphql.EnumValueConfig{Value: path}
+		}
+		fieldType = graphql.NewEnum(graphql.EnumConfig{
+			Name:        objectName + "Field",
+			Description: "The fields of the list items which can be used to filter or sort, nested fields are joined by '_'.",
+			Values:      values,
+		})
+	}
+
+	var filter *graphql.InputObject
+	filter = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        objectName + "Filter",
+		Description: "A list filter, either a single 'Operation' with optional 'Field' and 'Argument' or one of 'And', 'Or' or 'Not' grouping other filters.",
+		Fields: graphql.InputObjectConfigFieldMapThunk(func() graphql.InputObjectConfigFieldMap {
+			return graphql.InputObjectConfigFieldMap{
+				"Field": &graphql.InputObjectFieldConfig{
+					Type:        fieldType,
+					Description: "The field to filter on, the list items themselves if not specified.",
+				},
+				"Operation": &graphql.InputObjectFieldConfig{
+					Type: ob.listOperationEnum(),
+				},
+				"Argument": &graphql.InputObjectFieldConfig{
+					Type:        ob.filterArgumentScalar(),
+					Description: "The argument of the operation, ie '{Value: 10}'.",
+				},
+				filterAnd: &graphql.InputObjectFieldConfig{
+					Type: graphql.NewList(graphql.NewNonNull(filter)),
+				},
+				filterOr: &graphql.InputObjectFieldConfig{
+					Type: graphql.NewList(graphql.NewNonNull(filter)),
+				},
+				filterNot: &graphql.InputObjectFieldConfig{
+					Type: filter,
+				},
+			}
+		}),
+	})
+
+	sortKey := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        objectName + "Sort",
+		Description: "A sort key for the list.",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"Field": &graphql.InputObjectFieldConfig{
+				Type:        fieldType,
+				Description: "The field to sort by, the list items themselves if not specified.",
+			},
+			"Order": &graphql.InputObjectFieldConfig{
+				Type: ob.sortOrderEnum(),
+			},
+			"Nulls": &graphql.InputObjectFieldConfig{
+				Type:        ob.sortNullsEnum(),
+				Description: "Where items with a null or missing value are placed, last in ascending order and first in descending order by default.",
+			},
+		},
+	})
+
+	args := listFieldArguments()
+	args[filterArgumentName] = &graphql.ArgumentConfig{
+		Description: `A List Filter expression such as '{Field: position, Operation: LTE, Argument: {Value: 10}}'`,
+		Type:        filter,
+	}
+	args[sortArgumentName] = &graphql.ArgumentConfig{
+		Description: `Sort the list, ie '{Field: position, Order: ASC}' or by multiple keys '[{Field: priority, Order: DESC}, {Field: position}]'`,
+		Type:        graphql.NewList(graphql.NewNonNull(sortKey)),
+	}
+	return args, fieldType
+}
+
+// listOperationEnum returns the enum of list operations shared by all typed filters, it is created on first use from
+// the operations in ListOperations and ListTransforms.
+func (ob *ObjectBuilder) listOperationEnum() *graphql.Enum {
+	if ob.listOperation != nil {
+		return ob.listOperation
+	}
+
+	values := graphql.EnumValueConfigMap{}
+	add := func(operation string) {
+		name, ok := operationEnumNames[operation]
+		if !ok {
+			name = strings.Replace(operation, " ", "_", -1)
+		}
+		if !nameIsValidGraphQL(name) {
+			panic(fmt.Sprintf("list operation %q can't be used with typed list arguments, %q is not a valid GraphQL enum value", operation, name))
+		}
+		values[name] = &graphql.EnumValueConfig{Value: operation}
+	}
+	for operation := range ListOperations {
+		add(operation)
+	}
+	for operation := range ListTransforms {
+		add(operation)
+	}
+
+	ob.listOperation = graphql.NewEnum(graphql.EnumConfig{
+		Name:        ob.prefix + "ListOperation",
+		Description: "A list filter operation.",
+		Values:      values,
+	})
+	return ob.listOperation
+}
+
+// sortOrderEnum returns the enum of sort orders shared by all typed sorts, it is created on first use.
+func (ob *ObjectBuilder) sortOrderEnum() *graphql.Enum {
+	if ob.sortOrder != nil {
+		return ob.sortOrder
+	}
+
+	ob.sortOrder = graphql.NewEnum(graphql.EnumConfig{
+		Name: ob.prefix + "SortOrder",
+		Values: graphql.EnumValueConfigMap{
+			ascending:  &graphql.EnumValueConfig{Value: ascending, Description: "Ascending order, the default."},
+			descending: &graphql.EnumValueConfig{Value: descending, Description: "Descending order."},
+		},
+	})
+	return ob.sortOrder
+}
+
+// sortNullsEnum returns the enum of where nulls are placed shared by all typed sorts, it is created on first use.
+func (ob *ObjectBuilder) sortNullsEnum() *graphql.Enum {
+	if ob.sortNulls != nil {
+		return ob.sortNulls
+	}
+
+	ob.sortNulls = graphql.NewEnum(graphql.EnumConfig{
+		Name: ob.prefix + "SortNulls",
+		Values: graphql.EnumValueConfigMap{
+			nullsFirst: &graphql.EnumValueConfig{Value: nullsFirst, Description: "Items with a null value are placed first."},
+			nullsLast:  &graphql.EnumValueConfig{Value: nullsLast, Description: "Items with a null value are placed last."},
+		},
+	})
+	return ob.sortNulls
+}
+
+// filterArgumentScalar returns the scalar used for the Argument of typed filters, it is created on first use.
+// Arguments differ by operation so they remain an untyped JSON object.
+func (ob *ObjectBuilder) filterArgumentScalar() *graphql.Scalar {
+	if ob.filterArgument != nil {
+		return ob.filterArgument
+	}
+
+	ob.filterArgument = graphql.NewScalar(graphql.ScalarConfig{
+		Name:        ob.prefix + "ListFilterArgument",
+		Description: "A JSON object with the argument of a list filter operation, the fields needed depend on the operation.",
+		Serialize:   func(value interface{}) interface{} { return nil },
+		ParseValue: func(value interface{}) interface{} {
+			var arg map[string]interface{}
+			if err := decodeVariable(value, &arg); err != nil {
+				return nil
+			}
+			return normalizeJSONValue(arg)
+		},
+		ParseLiteral: func(valueAST ast.Value) interface{} {
+			if _, ok := valueAST.(*ast.ObjectValue); !ok {
+				return nil
+			}
+			arg, err := parseASTValue(valueAST)
+			if err != nil {
+				return nil
+			}
+			return arg
+		},
+	})
+	return ob.filterArgument
+}
+
+// fieldPaths returns the sorted paths of the fields within items of the given type as used in the Field of a filter
+// or sort. Nested structs are included along with the paths of their fields joined with FieldPathSeparator, fields of
+// root level embedded structs are found at the same level as the other fields. Structs within lists are not walked
+// into as there is no single value for their fields. If the type is not a struct no paths are returned.
+func fieldPaths(rType reflect.Type) []string {
+	found := make(map[string]bool)
+	walkFieldPaths(rType, "", make(map[reflect.Type]bool), found)
+
+	paths := make([]string, 0, len(found))
+	for path := range found {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// walkFieldPaths adds the path of each field in the struct type to found, visiting contains the types currently being
+// walked so recursive types end.
+func walkFieldPaths(rType reflect.Type, prefix string, visiting map[reflect.Type]bool, found map[string]bool) {
+	for rType.Kind() == reflect.Ptr {
+		rType = rType.Elem()
+	}
+	if rType.Kind() != reflect.Struct || rType.PkgPath() == "time" || visiting[rType] {
+		return
+	}
+	visiting[rType] = true
+	defer delete(visiting, rType)
+
+	for i := 0; i < rType.NumField(); i++ {
+		field := rType.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		if field.Anonymous {
+			walkFieldPaths(field.Type, prefix, visiting, found)
+			continue
+		}
+
+		name := fieldName(field)
+		switch name {
+		case "", "true", "false", "null":
+			continue
+		}
+		path := name
+		if prefix != "" {
+			path = prefix + FieldPathSeparator + name
+		}
+		found[path] = true
+		walkFieldPaths(field.Type, path, visiting, found)
+	}
+}
@@ -0,0 +1,246 @@
+package gql
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GannettDigital/graphql"
+)
+
+func TestTypedListArguments(t *testing.T) {
+	type leaf struct {
+		Name string
+	}
+	type item struct {
+		Name   string
+		Value  int
+		Leaf   leaf
+		Parent *leaf
+		Tags   []string
+	}
+	type testStruct struct {
+		Items      []item
+		StringList []string
+	}
+	testData := testStruct{
+		Items: []item{
+			{Name: "c", Value: 3, Leaf: leaf{Name: "y"}, Tags: []string{"x"}},
+			{Name: "a", Value: 1, Leaf: leaf{Name: "x"}},
+			{Name: "d", Value: 4, Leaf: leaf{Name: "x"}, Tags: []string{"x", "y"}},
+			{Name: "b", Value: 2, Leaf: leaf{Name: "y"}},
+		},
+		StringList: []string{"b", "a", "c"},
+	}
+
+	tests := []struct {
+		description string
+		query       string
+		variables   map[string]interface{}
+		want        string
+		wantErr     string
+	}{
+		{
+			description: "Inline filter and sort",
+			query:       `query { q { items(filter: {Field: value, Operation: GT, Argument: {Value: 1}}, sort: {Field: name, Order: DESC, Nulls: LAST}) { name } } }`,
+			want:        `{"data":{"q":{"items":[{"name":"d"},{"name":"c"},{"name":"b"}]}}}`,
+		},
+		{
+			description: "Nested field, boolean group and multiple sort keys",
+			query:       `query { q { items(filter: {Or: [{Field: leaf_name, Operation: EQ, Argument: {Value: "x"}}, {Not: {Field: tags, Operation: LENGTH, Argument: {Value: 0}}}]}, sort: [{Field: leaf_name}, {Field: value, Order: DESC}]) { name } } }`,
+			want:        `{"data":{"q":{"items":[{"name":"d"},{"name":"a"},{"name":"c"}]}}}`,
+		},
+		{
+			description: "Filter with a transform",
+			query:       `query { q { items(filter: {And: [{Field: value, Operation: NOT_IN, Argument: {Values: [2]}}, {Operation: LIMIT, Argument: {Value: 2}}]}) { name } } }`,
+			want:        `{"data":{"q":{"items":[{"name":"c"},{"name":"a"}]}}}`,
+		},
+		{
+			description: "Filter and sort variables",
+			query:       `query($f: teststruct_itemsFilter, $s: [teststruct_itemsSort!]) { q { items(filter: $f, sort: $s) { name } } }`,
+			variables: map[string]interface{}{
+				"f": map[string]interface{}{"Field": "value", "Operation": "LTE", "Argument": map[string]interface{}{"Value": 3}},
+				"s": map[string]interface{}{"Field": "name"},
+			},
+			want: `{"data":{"q":{"items":[{"name":"a"},{"name":"b"},{"name":"c"}]}}}`,
+		},
+		{
+			description: "Filtered total, groups and list of scalars",
+			query:       `query { q { filteredTotalItems(filter: {Field: value, Operation: GTE, Argument: {Value: 3}}) itemsGroups(groupBy: leaf_name) { key count } stringlist(sort: {Order: ASC}) } }`,
+			want:        `{"data":{"q":{"filteredTotalItems":2,"itemsGroups":[{"count":2,"key":"x"},{"count":2,"key":"y"}],"stringlist":["a","b","c"]}}}`,
+		},
+		{
+			description: "Invalid field",
+			query:       `query { q { items(filter: {Field: missing, Operation: EQ, Argument: {Value: 1}}) { name } } }`,
+			wantErr:     `Argument "filter" has invalid value`,
+		},
+		{
+			description: "Invalid operation",
+			query:       `query { q { items(filter: {Field: value, Operation: ABOUT, Argument: {Value: 1}}) { name } } }`,
+			wantErr:     `Argument "filter" has invalid value`,
+		},
+		{
+			description: "Invalid nested operation",
+			query:       `query { q { items(filter: {Not: {Field: value, Operation: "=="}}) { name } } }`,
+			wantErr:     `Argument "filter" has invalid value`,
+		},
+		{
+			description: "Invalid sort order",
+			query:       `query { q { items(sort: {Field: name, Order: UP}) { name } } }`,
+			wantErr:     `Argument "sort" has invalid value`,
+		},
+		{
+			description: "Invalid sort nulls",
+			query:       `query { q { items(sort: {Field: name, Nulls: MIDDLE}) { name } } }`,
+			wantErr:     `Argument "sort" has invalid value`,
+		},
+		{
+			description: "Invalid field variable",
+			query:       `query($f: teststruct_itemsFilter) { q { items(filter: $f) { name } } }`,
+			variables:   map[string]interface{}{"f": map[string]interface{}{"Field": "missing", "Operation": "EQ"}},
+			wantErr:     `Variable "$f" got invalid value`,
+		},
+	}
+
+	ob, err := NewObjectBuilder([]interface{}{testStruct{}}, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ob.SetTypedListArguments(true)
+	ob.SetFilteredCountFields(true)
+	ob.SetGroupFields(true)
+	types := ob.BuildTypes()
+	s, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"q": &graphql.Field{
+					Type: types[0],
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return testData, nil
+					},
+				},
+			},
+		}),
+		Types: types,
+	})
+	if err != nil {
+		t.Fatalf("failed to build schema: %v", err)
+	}
+
+	for _, test := range tests {
+		resp := graphql.Do(graphql.Params{Context: context.Background(), Schema: s, RequestString: test.query, VariableValues: test.variables})
+		if test.wantErr != "" {
+			if len(resp.Errors) == 0 {
+				t.Errorf("Test %q - got no errors, want %q", test.description, test.wantErr)
+			} else if got := resp.Errors[0].Message; !strings.Contains(got, test.wantErr) {
+				t.Errorf("Test %q - got error %q, want %q", test.description, got, test.wantErr)
+			}
+			continue
+		}
+		if len(resp.Errors) != 0 {
+			t.Errorf("Test %q - got errors %v", test.description, resp.Errors)
+			continue
+		}
+
+		gotBytes, err := json.Marshal(resp)
+		if err != nil {
+			t.Errorf("Test %q - failed to Marshal: %v", test.description, err)
+		}
+		if got, want := string(gotBytes), test.want; got != want {
+			t.Errorf("Test %q - got %v, want %v", test.description, got, want)
+		}
+	}
+}
+
+func TestFieldPaths(t *testing.T) {
+	type leaf struct {
+		Name string
+		Next *leaf
+	}
+	type Base struct {
+		ID string `json:"id"`
+	}
+	type item struct {
+		Base
+		Title     string `json:"title"`
+		Skipped   string `json:"-"`
+		Leaf      leaf
+		Children  []leaf
+		unexposed string
+	}
+
+	tests := []struct {
+		description string
+		rType       reflect.Type
+		want        []string
+	}{
+		{
+			description: "Struct with embedded, nested, recursive and list fields",
+			rType:       reflect.TypeOf(item{}),
+			want:        []string{"children", "id", "leaf", "leaf_name", "leaf_next", "title"},
+		},
+		{
+			description: "Pointer to a struct",
+			rType:       reflect.TypeOf(&leaf{}),
+			want:        []string{"name", "next"},
+		},
+		{
+			description: "Not a struct",
+			rType:       reflect.TypeOf(""),
+			want:        []string{},
+		},
+	}
+
+	for _, test := range tests {
+		if got := fieldPaths(test.rType); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Test %q - got %v, want %v", test.description, got, test.want)
+		}
+	}
+}
+
+func TestListOperationEnum(t *testing.T) {
+	tests := []struct {
+		description string
+		operation   string
+		wantName    string
+		wantPanic   bool
+	}{
+		{
+			description: "Spaces replaced",
+			operation:   "SOUNDS LIKE",
+			wantName:    "SOUNDS_LIKE",
+		},
+		{
+			description: "Invalid name",
+			operation:   "~=",
+			wantPanic:   true,
+		},
+	}
+
+	for _, test := range tests {
+		func() {
+			ListOperations[test.operation] = NewEqualComparator
+			defer delete(ListOperations, test.operation)
+			defer func() {
+				if r := recover(); (r != nil) != test.wantPanic {
+					t.Errorf("Test %q - got panic %v, want panic %t", test.description, r, test.wantPanic)
+				}
+			}()
+
+			ob := &ObjectBuilder{}
+			enum := ob.listOperationEnum()
+			var found bool
+			for _, value := range enum.Values() {
+				if value.Name == test.wantName && value.Value == test.operation {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("Test %q - enum value %q not found", test.description, test.wantName)
+			}
+		}()
+	}
+}
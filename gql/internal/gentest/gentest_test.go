@@ -0,0 +1,359 @@
+package gentest
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GannettDigital/graphql"
+	"github.com/GannettDigital/graphql-gen/gql"
+	"github.com/GannettDigital/graphql/testutil"
+)
+
+var (
+	score    = 4.5
+	modified = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	article  = Article{
+		Base:      Base{ID: "a1", Type: "article", Modified: modified},
+		Meta:      &Meta{Source: "wire"},
+		Title:     "Article",
+		Position:  2,
+		Published: true,
+		Author:    Contributor{Name: "Ann", Role: "writer", Score: &score},
+		Contributors: []Contributor{
+			{Name: "Bob", Role: "editor"},
+			{Name: "Cat", Role: "writer", Score: &score},
+			{Name: "Dan", Role: "writer"},
+		},
+		Tags:     []Tag{{Name: "news", Weight: 1}, {Name: "local", Weight: 2, Parent: &Tag2{Name: "news"}}},
+		Keywords: []string{"b", "a"},
+		Extra:    map[string]interface{}{"key": "value"},
+	}
+	video = Video{
+		Base:     Base{ID: "v1", Type: "video", Legacy: "old"},
+		Title:    "Video",
+		Duration: 1.5,
+		Chapters: []int{3, 1, 2},
+	}
+)
+
+// configs are the ObjectBuilder configurations the generated code is compared with reflection for, along with queries
+// using the features configured.
+var configs = []struct {
+	description string
+	prefix      string
+	additions   func() map[string][]*graphql.Field
+	configure   func(ob *gql.ObjectBuilder)
+	queries     []string
+}{
+	{
+		description: "Default",
+		additions:   func() map[string][]*graphql.Field { return nil },
+		configure:   func(ob *gql.ObjectBuilder) {},
+		queries: []string{
+			`query { q(id: "a1") { id type modifieddate legacy ... on article { title position views published source extra
+  author { name role score } editor { name } section { name path } keywords
+  contributors(filter: {Field: "role", Operation: "==", Argument: {Value: "writer"}}) { name }
+  tags(sort: {Field: "weight", Order: "DESC"}) { name parent { name } } } } }`,
+			`query { q(id: "v1") { id legacy ... on video { title duration chapters tags { name } } } }`,
+			`query { q(id: "a1") { ... on article { totalContributors contributorsAggregate(field: "score") { count sum } } } }`,
+		},
+	},
+	{
+		description: "Prefix, connections, filtered counts, groups, aggregates and field additions",
+		prefix:      "prefix",
+		additions: func() map[string][]*graphql.Field {
+			added := func(name string) []*graphql.Field {
+				return []*graphql.Field{{
+					Name:    name,
+					Type:    graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) { return name, nil },
+				}}
+			}
+			return map[string][]*graphql.Field{
+				"prefixBase":           added("baseadded"),
+				"prefixarticle_author": added("authoradded"),
+				"prefixvideo":          added("videoadded"),
+			}
+		},
+		configure: func(ob *gql.ObjectBuilder) {
+			ob.SetConnectionMode(gql.ListAndConnectionFields)
+			ob.SetFilteredCountFields(true)
+			ob.SetGroupFields(true)
+			ob.SetAggregateFields(true)
+		},
+		queries: []string{
+			`query { q(id: "a1") { id baseadded ... on prefixarticle { author { name authoradded }
+  contributorsConnection(first: 2) { edges { node { name } } pageInfo { hasNextPage } totalCount }
+  filteredTotalContributors(filter: {Field: "score", Operation: "IS NULL"}) contributorsGroups(groupBy: "role") { key count }
+  tagsAggregate(field: "weight") { sum max } } } }`,
+			`query { q(id: "v1") { ... on prefixvideo { videoadded chaptersAggregate { sum } } } }`,
+		},
+	},
+	{
+		description: "Typed arguments",
+		additions:   func() map[string][]*graphql.Field { return nil },
+		configure:   func(ob *gql.ObjectBuilder) { ob.SetTypedListArguments(true) },
+		queries: []string{
+			`query { q(id: "a1") { ... on article { contributors(filter: {Field: score, Operation: IS_NOT_NULL}, sort: {Field: name, Order: DESC}) { name }
+  tags(sort: {Field: parent_name, Nulls: LAST}) { name } keywords(sort: {Order: ASC}) } } }`,
+		},
+	},
+}
+
+func TestBuildGraphQLTypes(t *testing.T) {
+	for _, cfg := range configs {
+		reflective, err := gql.NewObjectBuilder([]interface{}{Article{}, Video{}}, cfg.prefix, cfg.additions())
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg.configure(reflective)
+		wantSchema := testSchema(t, reflective.BuildInterfaces(), reflective.BuildTypes())
+
+		generated, err := gql.NewObjectBuilder(nil, cfg.prefix, cfg.additions())
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg.configure(generated)
+		interfaces, types := BuildGraphQLTypes(generated)
+		gotSchema := testSchema(t, interfaces, types)
+
+		for _, query := range append([]string{testutil.IntrospectionQuery}, cfg.queries...) {
+			want := normalizedResponse(t, wantSchema, query)
+			if got := normalizedResponse(t, gotSchema, query); !reflect.DeepEqual(got, want) {
+				gotBytes, _ := json.Marshal(got)
+				wantBytes, _ := json.Marshal(want)
+				t.Errorf("Test %q - query %q\ngot  %s\nwant %s", cfg.description, query, gotBytes, wantBytes)
+			}
+		}
+	}
+}
+
+func TestGeneratedResolvers(t *testing.T) {
+	tests := []struct {
+		description string
+		query       string
+		want        string
+	}{
+		{
+			description: "Article fields",
+			query:       `query { q(id: "a1") { id modifieddate legacy ... on article { title source views author { name score } editor { name } keywords } } }`,
+			want:        `{"data":{"q":{"author":{"name":"Ann","score":4.5},"editor":null,"id":"a1","keywords":["b","a"],"legacy":"","modifieddate":"2020-01-02T03:04:05Z","source":"wire","title":"Article","views":0}}}`,
+		},
+		{
+			description: "Filtered and sorted lists",
+			query:       `query { q(id: "a1") { ... on article { contributors(filter: {Field: "score", Operation: "IS NOT NULL"}) { name } tags(sort: {Field: "parent_name", Order: "DESC"}) { name } keywords(sort: {Order: "ASC"}) } } }`,
+			want:        `{"data":{"q":{"contributors":[{"name":"Cat"}],"keywords":["a","b"],"tags":[{"name":"news"},{"name":"local"}]}}}`,
+		},
+		{
+			description: "Video fields",
+			query:       `query { q(id: "v1") { id legacy ... on video { duration chapters(sort: {Order: "DESC"}) tags { name } } } }`,
+			want:        `{"data":{"q":{"chapters":[3,2,1],"duration":1.5,"id":"v1","legacy":"old","tags":[]}}}`,
+		},
+	}
+
+	ob, err := gql.NewObjectBuilder(nil, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interfaces, types := BuildGraphQLTypes(ob)
+	s := testSchema(t, interfaces, types)
+
+	for _, test := range tests {
+		resp := graphql.Do(graphql.Params{Context: context.Background(), Schema: s, RequestString: test.query})
+		if len(resp.Errors) != 0 {
+			t.Errorf("Test %q - got errors %v", test.description, resp.Errors)
+			continue
+		}
+
+		gotBytes, err := json.Marshal(resp)
+		if err != nil {
+			t.Errorf("Test %q - failed to Marshal: %v", test.description, err)
+		}
+		if got, want := string(gotBytes), test.want; got != want {
+			t.Errorf("Test %q - got %v, want %v", test.description, got, want)
+		}
+	}
+}
+
+func TestGeneratedQueriedFields(t *testing.T) {
+	query := `query { q(id: "a1") { id ... on article { title source editor { name } author { name } contributors { name } } } }`
+
+	reflective, err := gql.NewObjectBuilder([]interface{}{Article{}, Video{}}, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := queriedFields(t, testSchema(t, reflective.BuildInterfaces(), reflective.BuildTypes()), query)
+
+	generated, err := gql.NewObjectBuilder(nil, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interfaces, types := BuildGraphQLTypes(generated)
+	got := queriedFields(t, testSchema(t, interfaces, types), query)
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Test %q - got %v, want %v", "Queried fields", got, want)
+	}
+}
+
+func TestResolveGraphQLField(t *testing.T) {
+	tests := []struct {
+		description string
+		source      gql.FieldResolver
+		name        string
+		want        interface{}
+		wantFound   bool
+	}{
+		{
+			description: "Field",
+			source:      &article,
+			name:        "title",
+			want:        "Article",
+			wantFound:   true,
+		},
+		{
+			description: "Field of an embedded struct",
+			source:      &article,
+			name:        "id",
+			want:        "a1",
+			wantFound:   true,
+		},
+		{
+			description: "Field of a nil embedded pointer",
+			source:      &Article{},
+			name:        "source",
+			wantFound:   true,
+		},
+		{
+			description: "Field not in the schema",
+			source:      &Article{Skipped: 1},
+			name:        "skipped",
+			want:        int32(1),
+			wantFound:   true,
+		},
+		{
+			description: "Missing field",
+			source:      &video,
+			name:        "source",
+		},
+	}
+
+	for _, test := range tests {
+		got, found := test.source.ResolveGraphQLField(test.name)
+		if found != test.wantFound || !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Test %q - got %v %t, want %v %t", test.description, got, found, test.want, test.wantFound)
+		}
+		if want := gql.ExtractField(test.source, test.name); !reflect.DeepEqual(got, want) {
+			t.Errorf("Test %q - got %v, ExtractField returned %v", test.description, got, want)
+		}
+	}
+}
+
+// testSchema returns a schema with a query 'q' returning the article or video with the given ID as the Base interface.
+// The article is returned by pointer and the video by value as the generated resolvers handle both.
+func testSchema(t *testing.T, interfaces map[string]*graphql.Interface, types []graphql.Type) graphql.Schema {
+	s, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"q": &graphql.Field{
+					Type: interfaces["Base"],
+					Args: graphql.FieldConfigArgument{
+						"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						if p.Args["id"] == video.ID {
+							return video, nil
+						}
+						return &article, nil
+					},
+				},
+			},
+		}),
+		Types: types,
+	})
+	if err != nil {
+		t.Fatalf("failed to build schema: %v", err)
+	}
+	return s
+}
+
+// fieldRecorder is a gql.QueryReporter recording the fields queried.
+type fieldRecorder struct {
+	mux    sync.Mutex
+	fields []string
+}
+
+func (r *fieldRecorder) QueriedField(field string) error {
+	r.mux.Lock()
+	r.fields = append(r.fields, field)
+	r.mux.Unlock()
+	return nil
+}
+
+// queriedFields runs the query and returns the sorted fields reported to a gql.QueryReporter.
+func queriedFields(t *testing.T, s graphql.Schema, query string) []string {
+	recorder := &fieldRecorder{}
+	ctx := context.WithValue(context.Background(), gql.QueryReporterContextKey, recorder)
+	resp := graphql.Do(graphql.Params{Context: ctx, Schema: s, RequestString: query})
+	if len(resp.Errors) != 0 {
+		t.Errorf("query %q failed: %v", query, resp.Errors)
+	}
+	sort.Strings(recorder.fields)
+	return recorder.fields
+}
+
+// normalizedResponse runs the query and returns the decoded JSON response with all lists sorted so the order of maps
+// within the schema doesn't matter.
+func normalizedResponse(t *testing.T, s graphql.Schema, query string) interface{} {
+	resp := graphql.Do(graphql.Params{Context: context.Background(), Schema: s, RequestString: query})
+	if len(resp.Errors) != 0 {
+		t.Errorf("query %q failed: %v", query, resp.Errors)
+	}
+	respBytes, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("failed to Marshal: %v", err)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(respBytes, &decoded); err != nil {
+		t.Fatalf("failed to Unmarshal: %v", err)
+	}
+	return sortLists(decoded)
+}
+
+// sortLists sorts every list within the decoded JSON value by the JSON of its items.
+func sortLists(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = sortLists(item)
+		}
+	case []interface{}:
+		keys := make([]string, len(v))
+		for i, item := range v {
+			v[i] = sortLists(item)
+			keyBytes, _ := json.Marshal(v[i])
+			keys[i] = string(keyBytes)
+		}
+		sort.Sort(byKey{keys: keys, values: v})
+	}
+	return value
+}
+
+// byKey sorts values by the corresponding keys.
+type byKey struct {
+	keys   []string
+	values []interface{}
+}
+
+func (b byKey) Len() int           { return len(b.keys) }
+func (b byKey) Less(i, j int) bool { return b.keys[i] < b.keys[j] }
+func (b byKey) Swap(i, j int) {
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+	b.values[i], b.values[j] = b.values[j], b.values[i]
+}
@@ -0,0 +1,715 @@
+// Code generated by graphql-gen. DO NOT EDIT.
+
+package gentest
+
+import (
+	"github.com/GannettDigital/graphql"
+	"github.com/GannettDigital/graphql-gen/gql"
+)
+
+// BuildGraphQLTypes builds the GraphQL interfaces and types for the Article and Video structs without reflection,
+// it is the equivalent of BuildInterfaces and BuildTypes for an ObjectBuilder of the structs.
+// The ObjectBuilder should be created with no structs and configured as usual.
+func BuildGraphQLTypes(ob *gql.ObjectBuilder) (map[string]*graphql.Interface, []graphql.Type) {
+	interfaces := make(map[string]*graphql.Interface)
+	interfaces["Base"] = ob.BuildStaticInterface("Base", graphqlBaseFields)
+	interfaces["Meta"] = ob.BuildStaticInterface("Meta", graphqlMetaFields)
+
+	types := []graphql.Type{
+		ob.BuildStaticType("Article", []string{"Base", "Meta"}, graphqlArticleFields),
+		ob.BuildStaticType("Video", []string{"Base"}, graphqlVideoFields),
+	}
+	return interfaces, types
+}
+
+func graphqlBaseFields(ob *gql.ObjectBuilder, parent string) graphql.Fields {
+	fields := graphql.Fields{}
+	fields["id"] = &graphql.Field{
+		Name: "id",
+		Type: graphql.NewNonNull(graphql.String),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			var s *Base
+			switch source := p.Source.(type) {
+			case Base:
+				s = &source
+			case *Base:
+				s = source
+			}
+			if s == nil {
+				return gql.ResolveByField("id", parent)(p)
+			}
+			if err := gql.ReportQueriedField(p, "id", parent); err != nil {
+				return nil, err
+			}
+			return s.ID, nil
+		},
+		ResolveSerial: true,
+		Description:   "The unique ID",
+	}
+	fields["type"] = &graphql.Field{
+		Name: "type",
+		Type: graphql.NewNonNull(graphql.String),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			var s *Base
+			switch source := p.Source.(type) {
+			case Base:
+				s = &source
+			case *Base:
+				s = source
+			}
+			if s == nil {
+				return gql.ResolveByField("type", parent)(p)
+			}
+			if err := gql.ReportQueriedField(p, "type", parent); err != nil {
+				return nil, err
+			}
+			return s.Type, nil
+		},
+		ResolveSerial: true,
+	}
+	fields["modifieddate"] = &graphql.Field{
+		Name: "modifieddate",
+		Type: graphql.NewNonNull(graphql.DateTime),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			var s *Base
+			switch source := p.Source.(type) {
+			case Base:
+				s = &source
+			case *Base:
+				s = source
+			}
+			if s == nil {
+				return gql.ResolveByField("modifieddate", parent)(p)
+			}
+			if err := gql.ReportQueriedField(p, "modifieddate", parent); err != nil {
+				return nil, err
+			}
+			return s.Modified, nil
+		},
+		ResolveSerial: true,
+	}
+	fields["legacy"] = &graphql.Field{
+		Name: "legacy",
+		Type: graphql.String,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			var s *Base
+			switch source := p.Source.(type) {
+			case Base:
+				s = &source
+			case *Base:
+				s = source
+			}
+			if s == nil {
+				return gql.ResolveByField("legacy", parent)(p)
+			}
+			if err := gql.ReportQueriedField(p, "legacy", parent); err != nil {
+				return nil, err
+			}
+			return s.Legacy, nil
+		},
+		ResolveSerial:     true,
+		DeprecationReason: "DEPRECATED: use id",
+	}
+	return fields
+}
+
+func graphqlMetaFields(ob *gql.ObjectBuilder, parent string) graphql.Fields {
+	fields := graphql.Fields{}
+	fields["source"] = &graphql.Field{
+		Name: "source",
+		Type: graphql.NewNonNull(graphql.String),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			var s *Meta
+			switch source := p.Source.(type) {
+			case Meta:
+				s = &source
+			case *Meta:
+				s = source
+			}
+			if s == nil {
+				return gql.ResolveByField("source", parent)(p)
+			}
+			if err := gql.ReportQueriedField(p, "source", parent); err != nil {
+				return nil, err
+			}
+			return s.Source, nil
+		},
+		ResolveSerial: true,
+	}
+	return fields
+}
+
+func graphqlContributorFields(ob *gql.ObjectBuilder, parent string) graphql.Fields {
+	fields := graphql.Fields{}
+	fields["name"] = &graphql.Field{
+		Name: "name",
+		Type: graphql.NewNonNull(graphql.String),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			var s *Contributor
+			switch source := p.Source.(type) {
+			case Contributor:
+				s = &source
+			case *Contributor:
+				s = source
+			}
+			if s == nil {
+				return gql.ResolveByField("name", parent)(p)
+			}
+			if err := gql.ReportQueriedField(p, "name", parent); err != nil {
+				return nil, err
+			}
+			return s.Name, nil
+		},
+		ResolveSerial: true,
+	}
+	fields["role"] = &graphql.Field{
+		Name: "role",
+		Type: graphql.String,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			var s *Contributor
+			switch source := p.Source.(type) {
+			case Contributor:
+				s = &source
+			case *Contributor:
+				s = source
+			}
+			if s == nil {
+				return gql.ResolveByField("role", parent)(p)
+			}
+			if err := gql.ReportQueriedField(p, "role", parent); err != nil {
+				return nil, err
+			}
+			return s.Role, nil
+		},
+		ResolveSerial: true,
+	}
+	fields["score"] = &graphql.Field{
+		Name: "score",
+		Type: graphql.Float,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			var s *Contributor
+			switch source := p.Source.(type) {
+			case Contributor:
+				s = &source
+			case *Contributor:
+				s = source
+			}
+			if s == nil {
+				return gql.ResolveByField("score", parent)(p)
+			}
+			if err := gql.ReportQueriedField(p, "score", parent); err != nil {
+				return nil, err
+			}
+			if s.Score == nil {
+				return nil, nil
+			}
+			return s.Score, nil
+		},
+		ResolveSerial: true,
+	}
+	return fields
+}
+
+func graphqlTag2Fields(ob *gql.ObjectBuilder, parent string) graphql.Fields {
+	fields := graphql.Fields{}
+	fields["name"] = &graphql.Field{
+		Name: "name",
+		Type: graphql.NewNonNull(graphql.String),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			var s *Tag2
+			switch source := p.Source.(type) {
+			case Tag2:
+				s = &source
+			case *Tag2:
+				s = source
+			}
+			if s == nil {
+				return gql.ResolveByField("name", parent)(p)
+			}
+			if err := gql.ReportQueriedField(p, "name", parent); err != nil {
+				return nil, err
+			}
+			return s.Name, nil
+		},
+		ResolveSerial: true,
+	}
+	return fields
+}
+
+func graphqlTagFields(ob *gql.ObjectBuilder, parent string) graphql.Fields {
+	fields := graphql.Fields{}
+	fields["name"] = &graphql.Field{
+		Name: "name",
+		Type: graphql.NewNonNull(graphql.String),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			var s *Tag
+			switch source := p.Source.(type) {
+			case Tag:
+				s = &source
+			case *Tag:
+				s = source
+			}
+			if s == nil {
+				return gql.ResolveByField("name", parent)(p)
+			}
+			if err := gql.ReportQueriedField(p, "name", parent); err != nil {
+				return nil, err
+			}
+			return s.Name, nil
+		},
+		ResolveSerial: true,
+	}
+	fields["weight"] = &graphql.Field{
+		Name: "weight",
+		Type: graphql.NewNonNull(graphql.Float),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			var s *Tag
+			switch source := p.Source.(type) {
+			case Tag:
+				s = &source
+			case *Tag:
+				s = source
+			}
+			if s == nil {
+				return gql.ResolveByField("weight", parent)(p)
+			}
+			if err := gql.ReportQueriedField(p, "weight", parent); err != nil {
+				return nil, err
+			}
+			return s.Weight, nil
+		},
+		ResolveSerial: true,
+	}
+	fields["parent"] = &graphql.Field{
+		Name: "parent",
+		Type: ob.BuildStaticObject("parent", parent, graphqlTag2Fields),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			var s *Tag
+			switch source := p.Source.(type) {
+			case Tag:
+				s = &source
+			case *Tag:
+				s = source
+			}
+			if s == nil {
+				return gql.ResolveByField("parent", parent)(p)
+			}
+			if err := gql.ReportQueriedField(p, "parent", parent); err != nil {
+				return nil, err
+			}
+			if s.Parent == nil {
+				return nil, nil
+			}
+			return s.Parent, nil
+		},
+		ResolveSerial: true,
+	}
+	return fields
+}
+
+func graphqlArticleSectionFields(ob *gql.ObjectBuilder, parent string) graphql.Fields {
+	fields := graphql.Fields{}
+	fields["name"] = &graphql.Field{
+		Name:          "name",
+		Type:          graphql.NewNonNull(graphql.String),
+		Resolve:       gql.ResolveByField("name", parent),
+		ResolveSerial: true,
+	}
+	fields["path"] = &graphql.Field{
+		Name:          "path",
+		Type:          graphql.NewNonNull(graphql.String),
+		Resolve:       gql.ResolveByField("path", parent),
+		ResolveSerial: true,
+	}
+	return fields
+}
+
+func graphqlArticleFields(ob *gql.ObjectBuilder, parent string) graphql.Fields {
+	fields := graphql.Fields{}
+	fields["title"] = &graphql.Field{
+		Name: "title",
+		Type: graphql.NewNonNull(graphql.String),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			var s *Article
+			switch source := p.Source.(type) {
+			case Article:
+				s = &source
+			case *Article:
+				s = source
+			}
+			if s == nil {
+				return gql.ResolveByField("title", parent)(p)
+			}
+			if err := gql.ReportQueriedField(p, "title", parent); err != nil {
+				return nil, err
+			}
+			return s.Title, nil
+		},
+		ResolveSerial: true,
+	}
+	fields["position"] = &graphql.Field{
+		Name: "position",
+		Type: graphql.NewNonNull(graphql.Int),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			var s *Article
+			switch source := p.Source.(type) {
+			case Article:
+				s = &source
+			case *Article:
+				s = source
+			}
+			if s == nil {
+				return gql.ResolveByField("position", parent)(p)
+			}
+			if err := gql.ReportQueriedField(p, "position", parent); err != nil {
+				return nil, err
+			}
+			return s.Position, nil
+		},
+		ResolveSerial: true,
+	}
+	fields["views"] = &graphql.Field{
+		Name: "views",
+		Type: graphql.Int,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			var s *Article
+			switch source := p.Source.(type) {
+			case Article:
+				s = &source
+			case *Article:
+				s = source
+			}
+			if s == nil {
+				return gql.ResolveByField("views", parent)(p)
+			}
+			if err := gql.ReportQueriedField(p, "views", parent); err != nil {
+				return nil, err
+			}
+			return s.Views, nil
+		},
+		ResolveSerial: true,
+	}
+	fields["published"] = &graphql.Field{
+		Name: "published",
+		Type: graphql.NewNonNull(graphql.Boolean),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			var s *Article
+			switch source := p.Source.(type) {
+			case Article:
+				s = &source
+			case *Article:
+				s = source
+			}
+			if s == nil {
+				return gql.ResolveByField("published", parent)(p)
+			}
+			if err := gql.ReportQueriedField(p, "published", parent); err != nil {
+				return nil, err
+			}
+			return s.Published, nil
+		},
+		ResolveSerial: true,
+	}
+	fields["author"] = &graphql.Field{
+		Name: "author",
+		Type: graphql.NewNonNull(ob.BuildStaticObject("author", parent, graphqlContributorFields)),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			var s *Article
+			switch source := p.Source.(type) {
+			case Article:
+				s = &source
+			case *Article:
+				s = source
+			}
+			if s == nil {
+				return gql.ResolveByField("author", parent)(p)
+			}
+			if err := gql.ReportQueriedField(p, "author", parent); err != nil {
+				return nil, err
+			}
+			return s.Author, nil
+		},
+		ResolveSerial: true,
+	}
+	fields["editor"] = &graphql.Field{
+		Name: "editor",
+		Type: ob.BuildStaticObject("editor", parent, graphqlContributorFields),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			var s *Article
+			switch source := p.Source.(type) {
+			case Article:
+				s = &source
+			case *Article:
+				s = source
+			}
+			if s == nil {
+				return gql.ResolveByField("editor", parent)(p)
+			}
+			if err := gql.ReportQueriedField(p, "editor", parent); err != nil {
+				return nil, err
+			}
+			if s.Editor == nil {
+				return nil, nil
+			}
+			return s.Editor, nil
+		},
+		ResolveSerial: true,
+	}
+	fields["contributors"] = &graphql.Field{
+		Name:          "contributors",
+		Type:          graphql.NewNonNull(graphql.NewList(ob.BuildStaticObject("contributors", parent, graphqlContributorFields))),
+		Resolve:       gql.ResolveByField("contributors", parent),
+		ResolveSerial: true,
+	}
+	ob.AddStaticListField(fields, "contributors", parent, []string{"name", "role", "score"}, true)
+	fields["tags"] = &graphql.Field{
+		Name:          "tags",
+		Type:          graphql.NewList(ob.BuildStaticObject("tags", parent, graphqlTagFields)),
+		Resolve:       gql.ResolveByField("tags", parent),
+		ResolveSerial: true,
+	}
+	ob.AddStaticListField(fields, "tags", parent, []string{"name", "parent", "parent_name", "weight"}, false)
+	fields["keywords"] = &graphql.Field{
+		Name:          "keywords",
+		Type:          graphql.NewNonNull(graphql.NewList(graphql.String)),
+		Resolve:       gql.ResolveByField("keywords", parent),
+		ResolveSerial: true,
+	}
+	ob.AddStaticListField(fields, "keywords", parent, nil, false)
+	fields["extra"] = &graphql.Field{
+		Name: "extra",
+		Type: graphql.Map,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			var s *Article
+			switch source := p.Source.(type) {
+			case Article:
+				s = &source
+			case *Article:
+				s = source
+			}
+			if s == nil {
+				return gql.ResolveByField("extra", parent)(p)
+			}
+			if err := gql.ReportQueriedField(p, "extra", parent); err != nil {
+				return nil, err
+			}
+			return s.Extra, nil
+		},
+		ResolveSerial: true,
+	}
+	fields["section"] = &graphql.Field{
+		Name: "section",
+		Type: graphql.NewNonNull(ob.BuildStaticObject("section", parent, graphqlArticleSectionFields)),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			var s *Article
+			switch source := p.Source.(type) {
+			case Article:
+				s = &source
+			case *Article:
+				s = source
+			}
+			if s == nil {
+				return gql.ResolveByField("section", parent)(p)
+			}
+			if err := gql.ReportQueriedField(p, "section", parent); err != nil {
+				return nil, err
+			}
+			return s.Section, nil
+		},
+		ResolveSerial: true,
+	}
+	return fields
+}
+
+func graphqlVideoFields(ob *gql.ObjectBuilder, parent string) graphql.Fields {
+	fields := graphql.Fields{}
+	fields["title"] = &graphql.Field{
+		Name: "title",
+		Type: graphql.NewNonNull(graphql.String),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			var s *Video
+			switch source := p.Source.(type) {
+			case Video:
+				s = &source
+			case *Video:
+				s = source
+			}
+			if s == nil {
+				return gql.ResolveByField("title", parent)(p)
+			}
+			if err := gql.ReportQueriedField(p, "title", parent); err != nil {
+				return nil, err
+			}
+			return s.Title, nil
+		},
+		ResolveSerial: true,
+	}
+	fields["duration"] = &graphql.Field{
+		Name: "duration",
+		Type: graphql.NewNonNull(graphql.Float),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			var s *Video
+			switch source := p.Source.(type) {
+			case Video:
+				s = &source
+			case *Video:
+				s = source
+			}
+			if s == nil {
+				return gql.ResolveByField("duration", parent)(p)
+			}
+			if err := gql.ReportQueriedField(p, "duration", parent); err != nil {
+				return nil, err
+			}
+			return s.Duration, nil
+		},
+		ResolveSerial: true,
+	}
+	fields["tags"] = &graphql.Field{
+		Name:          "tags",
+		Type:          graphql.NewNonNull(graphql.NewList(ob.BuildStaticObject("tags", parent, graphqlTagFields))),
+		Resolve:       gql.ResolveByField("tags", parent),
+		ResolveSerial: true,
+	}
+	ob.AddStaticListField(fields, "tags", parent, []string{"name", "parent", "parent_name", "weight"}, false)
+	fields["chapters"] = &graphql.Field{
+		Name:          "chapters",
+		Type:          graphql.NewList(graphql.Int),
+		Resolve:       gql.ResolveByField("chapters", parent),
+		ResolveSerial: true,
+	}
+	ob.AddStaticListField(fields, "chapters", parent, nil, false)
+	return fields
+}
+
+// ResolveGraphQLField implements gql.FieldResolver so the fields of Article are found without reflection.
+func (s *Article) ResolveGraphQLField(name string) (interface{}, bool) {
+	switch name {
+	case "base":
+		return s.Base, true
+	case "meta":
+		return s.Meta, true
+	case "title":
+		return s.Title, true
+	case "position":
+		return s.Position, true
+	case "views":
+		return s.Views, true
+	case "published":
+		return s.Published, true
+	case "author":
+		return s.Author, true
+	case "editor":
+		return s.Editor, true
+	case "contributors":
+		return s.Contributors, true
+	case "tags":
+		return s.Tags, true
+	case "keywords":
+		return s.Keywords, true
+	case "extra":
+		return s.Extra, true
+	case "section":
+		return s.Section, true
+	case "skipped":
+		return s.Skipped, true
+	case "id":
+		return s.Base.ID, true
+	case "type":
+		return s.Base.Type, true
+	case "modifieddate":
+		return s.Base.Modified, true
+	case "legacy":
+		return s.Base.Legacy, true
+	case "source":
+		if s.Meta == nil {
+			return nil, true
+		}
+		return s.Meta.Source, true
+	}
+	return nil, false
+}
+
+// ResolveGraphQLField implements gql.FieldResolver so the fields of Base are found without reflection.
+func (s *Base) ResolveGraphQLField(name string) (interface{}, bool) {
+	switch name {
+	case "id":
+		return s.ID, true
+	case "type":
+		return s.Type, true
+	case "modifieddate":
+		return s.Modified, true
+	case "legacy":
+		return s.Legacy, true
+	}
+	return nil, false
+}
+
+// ResolveGraphQLField implements gql.FieldResolver so the fields of Contributor are found without reflection.
+func (s *Contributor) ResolveGraphQLField(name string) (interface{}, bool) {
+	switch name {
+	case "name":
+		return s.Name, true
+	case "role":
+		return s.Role, true
+	case "score":
+		return s.Score, true
+	}
+	return nil, false
+}
+
+// ResolveGraphQLField implements gql.FieldResolver so the fields of Meta are found without reflection.
+func (s *Meta) ResolveGraphQLField(name string) (interface{}, bool) {
+	switch name {
+	case "source":
+		return s.Source, true
+	}
+	return nil, false
+}
+
+// ResolveGraphQLField implements gql.FieldResolver so the fields of Tag are found without reflection.
+func (s *Tag) ResolveGraphQLField(name string) (interface{}, bool) {
+	switch name {
+	case "name":
+		return s.Name, true
+	case "weight":
+		return s.Weight, true
+	case "parent":
+		return s.Parent, true
+	}
+	return nil, false
+}
+
+// ResolveGraphQLField implements gql.FieldResolver so the fields of Tag2 are found without reflection.
+func (s *Tag2) ResolveGraphQLField(name string) (interface{}, bool) {
+	switch name {
+	case "name":
+		return s.Name, true
+	}
+	return nil, false
+}
+
+// ResolveGraphQLField implements gql.FieldResolver so the fields of Video are found without reflection.
+func (s *Video) ResolveGraphQLField(name string) (interface{}, bool) {
+	switch name {
+	case "base":
+		return s.Base, true
+	case "title":
+		return s.Title, true
+	case "duration":
+		return s.Duration, true
+	case "tags":
+		return s.Tags, true
+	case "chapters":
+		return s.Chapters, true
+	case "id":
+		return s.Base.ID, true
+	case "type":
+		return s.Base.Type, true
+	case "modifieddate":
+		return s.Base.Modified, true
+	case "legacy":
+		return s.Base.Legacy, true
+	}
+	return nil, false
+}
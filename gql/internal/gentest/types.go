@@ -0,0 +1,69 @@
+// Package gentest holds structs covering the features of the ObjectBuilder along with the code generated for them by
+// cmd/graphql-gen, the tests check the generated schema and resolvers match those built with reflection.
+package gentest
+
+import "time"
+
+//go:generate go run -C ../../../cmd/graphql-gen . -type Article,Video ../../gql/internal/gentest
+
+// Base is embedded by both types so it becomes a GraphQL interface.
+type Base struct {
+	ID       string    `json:"id" description:"The unique ID"`
+	Type     string    `json:"type"`
+	Modified time.Time `json:"modified_date"`
+	Legacy   string    `json:"legacy,omitempty" description:"DEPRECATED: use id"`
+}
+
+// Contributor is a nested object.
+type Contributor struct {
+	Name  string   `json:"name"`
+	Role  string   `json:"role,omitempty"`
+	Score *float64 `json:"score,omitempty"`
+}
+
+// Tag is within a list.
+type Tag struct {
+	Name   string  `json:"name"`
+	Weight float64 `json:"weight"`
+	Parent *Tag2   `json:"parent,omitempty"`
+}
+
+// Tag2 is a nested pointer within a list item.
+type Tag2 struct {
+	Name string `json:"name"`
+}
+
+// Meta is embedded by pointer.
+type Meta struct {
+	Source string `json:"source"`
+}
+
+// Article is a root type.
+type Article struct {
+	Base
+	*Meta
+	Title        string                 `json:"title"`
+	Position     int                    `json:"position"`
+	Views        int64                  `json:"views,omitempty"`
+	Published    bool                   `json:"published"`
+	Author       Contributor            `json:"author"`
+	Editor       *Contributor           `json:"editor,omitempty"`
+	Contributors []Contributor          `json:"contributors" aggregate:"true"`
+	Tags         []Tag                  `json:"tags,omitempty"`
+	Keywords     []string               `json:"keywords"`
+	Extra        map[string]interface{} `json:"extra,omitempty"`
+	Section      struct {
+		Name string `json:"name"`
+		Path string `json:"path"`
+	} `json:"section"`
+	Skipped int32
+}
+
+// Video is a root type which also embeds Base.
+type Video struct {
+	Base
+	Title    string  `json:"title"`
+	Duration float32 `json:"duration"`
+	Tags     []Tag   `json:"tags"`
+	Chapters []int   `json:"chapters,omitempty"`
+}
@@ -0,0 +1,448 @@
+package gql
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GannettDigital/graphql"
+)
+
+const (
+	firstArgumentName  = "first"
+	afterArgumentName  = "after"
+	offsetArgumentName = "offset"
+	limitArgumentName  = "limit"
+
+	cursorPrefix    = "cursor:"
+	endCursorSuffix = "EndCursor"
+)
+
+// pageParameters define the page of a list to return, the items from start up to count items in length.
+// A count of -1 means there is no limit. With an after cursor the start is found in the list being paged, see bounds.
+type pageParameters struct {
+	start int
+	count int
+	after *cursorJSON
+	sort  []*sortParameters
+}
+
+// cursorJSON is the content of a cursor.
+//
+// Query identifies the filter and sort used to create the cursor, see cursorQuery. Offset is the position of the item
+// in the filtered and sorted list. When the list is sorted Keys holds the value of each sort key for the item and Ties
+// the number of items up to and including it which have the same values, the page after the cursor is found from these
+// so items added to or removed from the list before the cursor don't cause items to be skipped or repeated. Offset is
+// used for lists which aren't sorted or when a sort key has a value which can't be held in a cursor, a Sortable.
+type cursorJSON struct {
+	Offset int         `json:"o"`
+	Query  string      `json:"q"`
+	Keys   []cursorKey `json:"k,omitempty"`
+	Ties   int         `json:"t,omitempty"`
+}
+
+// cursorKey is the value of a sort key in a cursor, the value is formatted as a string based on its kind.
+type cursorKey struct {
+	Kind  sortKind `json:"k"`
+	Value string   `json:"v,omitempty"`
+}
+
+// parsePageParameters parses the first, after, offset and limit arguments of a list field. Either first and after
+// or offset and limit can be used but not both. If none of the arguments are specified nil is returned.
+// The query, from cursorQuery, is needed to check the after cursor was created with the same filter and sort and the
+// sort parameters to find the page after it.
+func parsePageParameters(args map[string]interface{}, sortParams []*sortParameters, query string) (*pageParameters, error) {
+	first, hasFirst, err := pageCountArgument(args, firstArgumentName)
+	if err != nil {
+		return nil, err
+	}
+	offset, hasOffset, err := pageCountArgument(args, offsetArgumentName)
+	if err != nil {
+		return nil, err
+	}
+	limit, hasLimit, err := pageCountArgument(args, limitArgumentName)
+	if err != nil {
+		return nil, err
+	}
+	rawAfter, hasAfter := args[afterArgumentName]
+	if hasAfter && rawAfter == nil {
+		hasAfter = false
+	}
+
+	if (hasFirst || hasAfter) && (hasOffset || hasLimit) {
+		return nil, fmt.Errorf("the %q and %q arguments can not be used with the %q and %q arguments",
+			firstArgumentName, afterArgumentName, offsetArgumentName, limitArgumentName)
+	}
+
+	params := pageParameters{count: -1}
+	switch {
+	case hasFirst || hasAfter:
+		if hasFirst {
+			params.count = first
+		}
+		if hasAfter {
+			after, ok := rawAfter.(string)
+			if !ok {
+				return nil, fmt.Errorf("the %q argument must be a cursor string", afterArgumentName)
+			}
+			cursor, err := decodeCursor(after, sortParams, query)
+			if err != nil {
+				return nil, err
+			}
+			params.after = cursor
+			params.sort = sortParams
+		}
+	case hasOffset || hasLimit:
+		params.start = offset
+		if hasLimit {
+			params.count = limit
+		}
+	default:
+		return nil, nil
+	}
+
+	return &params, nil
+}
+
+// ResolveListEndCursor accepts a cursor field name, a name of the list field, and a parent name. It returns the opaque
+// cursor of the last item in the page of the list chosen by the filter, sort and paging arguments, which are the same
+// as those of ResolveListField, so the next page of the list field can be requested with the 'after' argument. Null
+// is returned if the page is empty. It will also report the queried field to the QueryReporter and the arguments to the
+// QueryFunctionReporter if found in the context.
+func ResolveListEndCursor(cursorFieldName, listFieldName, parent string) graphql.FieldResolveFn {
+	return collectListErrors(func(p graphql.ResolveParams) (interface{}, error) {
+		if qr, ok := p.Context.Value(QueryReporterContextKey).(QueryReporter); ok && qr != nil {
+			if err := qr.QueriedField(fullFieldName(cursorFieldName, parent)); err != nil {
+				return nil, err
+			}
+		}
+
+		args, err := parseListArguments(p, cursorFieldName, parent)
+		if err != nil {
+			return nil, err
+		}
+
+		values, err := filteredListItems(p, args, listFieldName, parent)
+		if err != nil {
+			return nil, err
+		}
+		cursors := args.cursors(values)
+		start, end, err := args.page.bounds(cursors)
+		if err != nil {
+			return nil, err
+		}
+		if start == end {
+			return nil, nil
+		}
+		return cursors.cursor(end - 1), nil
+	})
+}
+
+// pageCountArgument returns the non-negative integer argument with the given name and whether it was specified.
+func pageCountArgument(args map[string]interface{}, name string) (int, bool, error) {
+	raw, ok := args[name]
+	if !ok || raw == nil {
+		return 0, false, nil
+	}
+	count, ok := raw.(int)
+	if !ok {
+		return 0, false, fmt.Errorf("the %q argument must be an integer", name)
+	}
+	if count < 0 {
+		return 0, false, fmt.Errorf("the %q argument must not be negative", name)
+	}
+	return count, true, nil
+}
+
+// apply returns the page of the list the cursors were created for.
+func (pp *pageParameters) apply(cursors *listCursors) ([]interface{}, error) {
+	start, end, err := pp.bounds(cursors)
+	if err != nil {
+		return nil, err
+	}
+	return cursors.list[start:end], nil
+}
+
+// bounds returns the start and end index of the page within the filtered and sorted list the cursors were created for.
+func (pp *pageParameters) bounds(cursors *listCursors) (int, int, error) {
+	length := len(cursors.list)
+	if pp == nil {
+		return 0, length, nil
+	}
+	start, end := pp.start, length
+	if pp.after != nil {
+		var err error
+		start, err = pp.after.next(cursors)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	if start > length {
+		start = length
+	}
+	if pp.count >= 0 && start+pp.count < end {
+		end = start + pp.count
+	}
+	return start, end, nil
+}
+
+// next returns the index of the first item after the cursor in the sorted list. Items which sort before the cursor
+// keys are skipped, as are the number of items with equal keys which were before or at the cursor.
+func (cj *cursorJSON) next(cursors *listCursors) (int, error) {
+	if cj.Keys == nil {
+		return cj.Offset + 1, nil
+	}
+
+	var compareErr error
+	compare := func(i int) int {
+		c, err := compareCursorKeys(cursors.keys(i), cj.Keys, cursors.sort)
+		if err != nil && compareErr == nil {
+			compareErr = err
+		}
+		return c
+	}
+
+	length := len(cursors.list)
+	first := sort.Search(length, func(i int) bool { return compare(i) >= 0 })
+	equal := sort.Search(length-first, func(i int) bool { return compare(first+i) > 0 })
+	if compareErr != nil {
+		return 0, compareErr
+	}
+	if cj.Ties < equal {
+		equal = cj.Ties
+	}
+	return first + equal, nil
+}
+
+// listCursors creates the cursors for the items of a filtered and sorted list and is used to find the page after a
+// cursor. The sort keys of an item are found at most once per resolve and the ties of the items in a page are counted
+// incrementally, so only the items compared by the search for the page and those within it are looked at.
+type listCursors struct {
+	list  []interface{}
+	sort  []*sortParameters
+	query string // from cursorQuery
+	key   [][]cursorKey
+	found []bool
+	ties  []int
+}
+
+func newListCursors(list []interface{}, sortParams []*sortParameters, query string) *listCursors {
+	lc := &listCursors{list: list, sort: sortParams, query: query}
+	if len(sortParams) > 0 {
+		lc.key = make([][]cursorKey, len(list))
+		lc.found = make([]bool, len(list))
+		lc.ties = make([]int, len(list))
+	}
+	return lc
+}
+
+// keys returns the sort keys of the item at the index, see sortKeys.
+func (lc *listCursors) keys(index int) []cursorKey {
+	if !lc.found[index] {
+		lc.key[index] = sortKeys(lc.list[index], lc.sort)
+		lc.found[index] = true
+	}
+	return lc.key[index]
+}
+
+// tieCount returns the number of items up to and including the one at the index which have the same sort keys. The
+// count of an earlier item is reused so counting the ties of each item of a page in order compares only neighbours.
+func (lc *listCursors) tieCount(index int) int {
+	if lc.ties[index] > 0 {
+		return lc.ties[index]
+	}
+	count := 1
+	for i := index - 1; i >= 0; i-- {
+		if c, err := compareCursorKeys(lc.keys(i), lc.keys(index), lc.sort); err != nil || c != 0 {
+			break
+		}
+		if lc.ties[i] > 0 {
+			count += lc.ties[i]
+			break
+		}
+		count++
+	}
+	lc.ties[index] = count
+	return count
+}
+
+// cursor returns the opaque cursor for the item at the index.
+func (lc *listCursors) cursor(index int) string {
+	cj := cursorJSON{Offset: index, Query: lc.query}
+	if len(lc.sort) > 0 {
+		cj.Keys = lc.keys(index)
+		if cj.Keys != nil {
+			cj.Ties = lc.tieCount(index)
+		}
+	}
+	raw, _ := json.Marshal(cj)
+	return base64.RawURLEncoding.EncodeToString(append([]byte(cursorPrefix), raw...))
+}
+
+// decodeCursor returns the content of an opaque cursor created by listCursors. An error is returned if the cursor
+// is invalid or was created for a list with a different filter or sort as it would not refer to the same item.
+func decodeCursor(cursor string, sortParams []*sortParameters, query string) (*cursorJSON, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(raw), cursorPrefix) {
+		return nil, errors.New("invalid cursor")
+	}
+	var cj cursorJSON
+	if err := json.Unmarshal(raw[len(cursorPrefix):], &cj); err != nil || cj.Offset < 0 {
+		return nil, errors.New("invalid cursor")
+	}
+	if cj.Keys != nil && len(cj.Keys) != len(sortParams) {
+		return nil, errors.New("invalid cursor")
+	}
+	if cj.Query != query {
+		return nil, errors.New("the cursor was created with a different filter or sort, it can only be used with the same filter and sort")
+	}
+	return &cj, nil
+}
+
+// cursorQuery identifies the filter and sort of a list for inclusion in a cursor, it is a hash of both.
+func cursorQuery(filter *listFilter, sortParams []*sortParameters) string {
+	h := fnv.New64a()
+	if filter != nil {
+		raw, _ := json.Marshal(filter.json)
+		h.Write(raw)
+	}
+	h.Write([]byte{0})
+	h.Write([]byte(sortDescription(sortParams)))
+	return strconv.FormatUint(h.Sum64(), 36)
+}
+
+// sortDescription describes the sort parameters for inclusion in a cursor.
+func sortDescription(sortParams []*sortParameters) string {
+	keys := make([]string, len(sortParams))
+	for i, params := range sortParams {
+		order := params.order
+		if order == "" {
+			order = ascending
+		}
+		keys[i] = params.field + " " + order
+		if params.nulls != "" {
+			keys[i] += " NULLS " + params.nulls
+		}
+	}
+	return strings.Join(keys, ",")
+}
+
+// sortKeys returns the value of each sort key for the list item, nil is returned if any value can't be held in a
+// cursor.
+func sortKeys(item interface{}, sortParams []*sortParameters) []cursorKey {
+	keys := make([]cursorKey, len(sortParams))
+	for i, params := range sortParams {
+		raw := item
+		if params.field != "" {
+			raw, _, _ = compileFieldPath(params.field).lookup(item)
+		}
+
+		value, kind := sortValue(raw)
+		key := cursorKey{Kind: kind}
+		switch kind {
+		case nilSortKind:
+		case timeSortKind:
+			key.Value = value.(time.Time).Format(time.RFC3339Nano)
+		case boolSortKind:
+			key.Value = strconv.FormatBool(value.(bool))
+		case numberSortKind:
+			switch n := value.(type) {
+			case int64:
+				key.Value = strconv.FormatInt(n, 10)
+			case uint64:
+				key.Value = strconv.FormatUint(n, 10)
+			case float64:
+				key.Value = strconv.FormatFloat(n, 'g', -1, 64)
+			}
+		case stringSortKind:
+			key.Value = value.(string)
+		default:
+			return nil
+		}
+		keys[i] = key
+	}
+	return keys
+}
+
+// compareCursorKeys compares the sort key values of two items in the same way as listSort. An error is returned if
+// the values are of different kinds, the cursor is from a list with a different type of values.
+func compareCursorKeys(a, b []cursorKey, sortParams []*sortParameters) (int, error) {
+	if a == nil {
+		return 0, errors.New("the cursor can't be used as the sort field values can't be compared")
+	}
+	for i, params := range sortParams {
+		if c, err := compareCursorKey(a[i], b[i], params); err != nil || c != 0 {
+			return c, err
+		}
+	}
+	return 0, nil
+}
+
+// compareCursorKey compares the values of a single sort key, null values are placed as given by the sort parameters.
+func compareCursorKey(a, b cursorKey, params *sortParameters) (int, error) {
+	nullOrder := 1
+	if params.nullsSortFirst() {
+		nullOrder = -1
+	}
+	switch {
+	case a.Kind == nilSortKind && b.Kind == nilSortKind:
+		return 0, nil
+	case a.Kind == nilSortKind:
+		return nullOrder, nil
+	case b.Kind == nilSortKind:
+		return -nullOrder, nil
+	case a.Kind != b.Kind:
+		return 0, fmt.Errorf("the cursor can't be used as the values of sort field %q have changed type", params.field)
+	}
+
+	var c int
+	switch a.Kind {
+	case timeSortKind:
+		at, errA := time.Parse(time.RFC3339Nano, a.Value)
+		bt, errB := time.Parse(time.RFC3339Nano, b.Value)
+		if errA != nil || errB != nil {
+			return 0, errors.New("invalid cursor")
+		}
+		switch {
+		case at.Before(bt):
+			c = -1
+		case at.After(bt):
+			c = 1
+		}
+	case boolSortKind:
+		c = strings.Compare(a.Value, b.Value) // "false" sorts before "true"
+	case numberSortKind:
+		an, errA := parseCursorNumber(a.Value)
+		bn, errB := parseCursorNumber(b.Value)
+		if errA != nil || errB != nil {
+			return 0, errors.New("invalid cursor")
+		}
+		c, _ = compareNumbers(an, bn)
+	case stringSortKind:
+		c = strings.Compare(a.Value, b.Value)
+	default:
+		return 0, errors.New("invalid cursor")
+	}
+
+	if params.order == descending {
+		c = -c
+	}
+	return c, nil
+}
+
+// parseCursorNumber parses a number formatted by sortKeys back to a canonical number as returned by coerceValue.
+func parseCursorNumber(value string) (interface{}, error) {
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return i, nil
+	}
+	if u, err := strconv.ParseUint(value, 10, 64); err == nil {
+		return u, nil
+	}
+	return strconv.ParseFloat(value, 64)
+}
@@ -0,0 +1,304 @@
+package gql
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParsePageParameters(t *testing.T) {
+	byName := []*sortParameters{{field: "name"}}
+	byNameQuery := cursorQuery(nil, byName)
+	list := []interface{}{map[string]interface{}{"name": "a"}}
+	cursor := newListCursors(list, byName, byNameQuery).cursor(0)
+	decoded, err := decodeCursor(cursor, byName, byNameQuery)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		description string
+		args        map[string]interface{}
+		sortParams  []*sortParameters
+		query       string
+		want        *pageParameters
+		wantErr     bool
+	}{
+		{
+			description: "No arguments",
+			args:        map[string]interface{}{"filter": nil},
+		},
+		{
+			description: "Offset and limit",
+			args:        map[string]interface{}{"offset": 2, "limit": 3},
+			want:        &pageParameters{start: 2, count: 3},
+		},
+		{
+			description: "Offset only",
+			args:        map[string]interface{}{"offset": 2},
+			want:        &pageParameters{start: 2, count: -1},
+		},
+		{
+			description: "First only",
+			args:        map[string]interface{}{"first": 2},
+			want:        &pageParameters{start: 0, count: 2},
+		},
+		{
+			description: "First and after",
+			args:        map[string]interface{}{"first": 2, "after": cursor},
+			sortParams:  byName,
+			query:       byNameQuery,
+			want:        &pageParameters{count: 2, after: decoded, sort: byName},
+		},
+		{
+			description: "After with a cursor from a different sort",
+			args:        map[string]interface{}{"after": cursor},
+			sortParams:  []*sortParameters{{field: "name", order: descending}},
+			query:       cursorQuery(nil, []*sortParameters{{field: "name", order: descending}}),
+			wantErr:     true,
+		},
+		{
+			description: "After with an invalid cursor",
+			args:        map[string]interface{}{"after": "bogus"},
+			wantErr:     true,
+		},
+		{
+			description: "First and offset",
+			args:        map[string]interface{}{"first": 2, "offset": 1},
+			wantErr:     true,
+		},
+		{
+			description: "Negative limit",
+			args:        map[string]interface{}{"limit": -1},
+			wantErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := parsePageParameters(test.args, test.sortParams, test.query)
+		if (err != nil) != test.wantErr {
+			t.Errorf("Test %q - got err %v, want err %t", test.description, err, test.wantErr)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Test %q - got %+v, want %+v", test.description, got, test.want)
+		}
+	}
+}
+
+func TestCursorQuery(t *testing.T) {
+	byValue, err := newListFilter(&listFilterJSON{Field: "value", Operation: ">", Argument: map[string]interface{}{"Value": 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName, err := newListFilter(&listFilterJSON{Field: "name", Operation: ">", Argument: map[string]interface{}{"Value": 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sortParams := []*sortParameters{{field: "name"}}
+
+	if got, want := cursorQuery(byValue, sortParams), cursorQuery(byValue, []*sortParameters{{field: "name", order: ascending}}); got != want {
+		t.Errorf("got %q, want the same query %q for an equivalent sort", got, want)
+	}
+	for _, other := range []string{
+		cursorQuery(byName, sortParams),
+		cursorQuery(nil, sortParams),
+		cursorQuery(byValue, nil),
+	} {
+		if other == cursorQuery(byValue, sortParams) {
+			t.Errorf("got the same query %q for a different filter or sort", other)
+		}
+	}
+}
+
+func TestCursorNext(t *testing.T) {
+	type item struct {
+		Name     string
+		Priority *int
+		Created  time.Time
+	}
+	one, two := 1, 2
+	day := func(d int) time.Time { return time.Date(2020, 1, d, 0, 0, 0, 0, time.UTC) }
+	a := item{Name: "a", Priority: &two, Created: day(1)}
+	b := item{Name: "b", Priority: &one, Created: day(2)}
+	c := item{Name: "c", Priority: &one, Created: day(3)}
+	d := item{Name: "d", Created: day(4)}
+	e := item{Name: "e", Priority: &one, Created: day(5)}
+
+	byPriority := []*sortParameters{{field: "priority", order: descending, nulls: nullsLast}}
+	byName := []*sortParameters{{field: "name"}}
+	byCreated := []*sortParameters{{field: "created", order: descending}}
+
+	tests := []struct {
+		description string
+		sortParams  []*sortParameters
+		list        []interface{} // the list the cursor is created from
+		index       int           // the index of the cursor item
+		next        []interface{} // the list after changes, paged with the cursor
+		want        int
+		wantErr     bool
+	}{
+		{
+			description: "Unchanged list",
+			sortParams:  byName,
+			list:        []interface{}{a, b, c},
+			index:       1,
+			next:        []interface{}{a, b, c},
+			want:        2,
+		},
+		{
+			description: "Item added before the cursor",
+			sortParams:  byName,
+			list:        []interface{}{b, c, d},
+			index:       1,
+			next:        []interface{}{a, b, c, d},
+			want:        3,
+		},
+		{
+			description: "Cursor item and an earlier item removed",
+			sortParams:  byName,
+			list:        []interface{}{a, b, c, d},
+			index:       2,
+			next:        []interface{}{b, d},
+			want:        1,
+		},
+		{
+			description: "Equal sort values",
+			sortParams:  byPriority,
+			list:        []interface{}{a, b, c, e, d},
+			index:       2,
+			next:        []interface{}{a, b, c, e, d},
+			want:        3,
+		},
+		{
+			description: "Equal sort values with an item added before the cursor",
+			sortParams:  byPriority,
+			list:        []interface{}{b, c, e, d},
+			index:       1,
+			next:        []interface{}{a, b, c, e, d},
+			want:        3,
+		},
+		{
+			description: "Null sort value",
+			sortParams:  byPriority,
+			list:        []interface{}{a, b, c, e, d},
+			index:       4,
+			next:        []interface{}{a, b, c, e, d},
+			want:        5,
+		},
+		{
+			description: "Descending time",
+			sortParams:  byCreated,
+			list:        []interface{}{e, d, c, b, a},
+			index:       2,
+			next:        []interface{}{e, d, b, a},
+			want:        2,
+		},
+		{
+			description: "Mixed integer and float sort values",
+			sortParams:  []*sortParameters{{}},
+			list:        []interface{}{1, 1.5, uint64(1 << 63)},
+			index:       1,
+			next:        []interface{}{1, 1.25, 1.5, 2, uint64(1 << 63)},
+			want:        3,
+		},
+		{
+			description: "Not sorted uses the offset",
+			list:        []interface{}{c, a, b},
+			index:       1,
+			next:        []interface{}{d, c, a, b},
+			want:        2,
+		},
+		{
+			description: "Sort values changed type",
+			sortParams:  byName,
+			list:        []interface{}{a, b},
+			index:       0,
+			next:        []interface{}{map[string]interface{}{"name": 1}},
+			wantErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		query := cursorQuery(nil, test.sortParams)
+		cursor, err := decodeCursor(newListCursors(test.list, test.sortParams, query).cursor(test.index), test.sortParams, query)
+		if err != nil {
+			t.Errorf("Test %q - failed to decode cursor: %v", test.description, err)
+			continue
+		}
+		got, err := cursor.next(newListCursors(test.next, test.sortParams, query))
+		if (err != nil) != test.wantErr {
+			t.Errorf("Test %q - got err %v, want err %t", test.description, err, test.wantErr)
+		}
+		if !test.wantErr && got != test.want {
+			t.Errorf("Test %q - got %d, want %d", test.description, got, test.want)
+		}
+	}
+}
+
+func TestListCursorsTies(t *testing.T) {
+	type item struct {
+		Name     string
+		Priority int
+	}
+	list := []interface{}{
+		item{Name: "a", Priority: 2}, item{Name: "b", Priority: 1}, item{Name: "c", Priority: 1}, item{Name: "d", Priority: 1},
+		item{Name: "e", Priority: 0},
+	}
+	byPriority := []*sortParameters{{field: "priority", order: descending}}
+	want := []int{1, 1, 2, 3, 1}
+
+	// The ties are counted the same whether the cursors are created in order, as for a page, or for a single item.
+	inOrder := newListCursors(list, byPriority, "")
+	for i := range list {
+		if got := inOrder.tieCount(i); got != want[i] {
+			t.Errorf("Test \"In order\" - item %d got %d ties, want %d", i, got, want[i])
+		}
+		if got := newListCursors(list, byPriority, "").tieCount(i); got != want[i] {
+			t.Errorf("Test \"Single item\" - item %d got %d ties, want %d", i, got, want[i])
+		}
+	}
+	if got, want := newListCursors(list, byPriority, "").cursor(3), inOrder.cursor(3); got != want {
+		t.Errorf("got cursor %q, want %q", got, want)
+	}
+}
+
+func TestPageParametersApply(t *testing.T) {
+	list := []interface{}{"a", "b", "c"}
+
+	tests := []struct {
+		description string
+		params      pageParameters
+		want        []interface{}
+	}{
+		{
+			description: "Within the list",
+			params:      pageParameters{start: 1, count: 1},
+			want:        []interface{}{"b"},
+		},
+		{
+			description: "No count",
+			params:      pageParameters{start: 1, count: -1},
+			want:        []interface{}{"b", "c"},
+		},
+		{
+			description: "Past the end of the list",
+			params:      pageParameters{start: 3, count: 1},
+			want:        []interface{}{},
+		},
+		{
+			description: "After a cursor",
+			params:      pageParameters{count: -1, after: &cursorJSON{Offset: 0}},
+			want:        []interface{}{"b", "c"},
+		},
+	}
+
+	for _, test := range tests {
+		got, err := test.params.apply(newListCursors(list, nil, ""))
+		if err != nil {
+			t.Errorf("Test %q - got err %v", test.description, err)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Test %q - got %v, want %v", test.description, got, test.want)
+		}
+	}
+}
@@ -3,7 +3,11 @@ package gql
 import (
 	"errors"
 	"fmt"
+	"math"
+	"reflect"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/GannettDigital/graphql"
 	"github.com/GannettDigital/graphql/language/ast"
@@ -12,34 +16,176 @@ import (
 const (
 	ascending  = "ASC"
 	descending = "DESC"
+
+	nullsFirst = "FIRST"
+	nullsLast  = "LAST"
 )
 
 var graphqlSortFilter = graphql.NewScalar(graphql.ScalarConfig{
 	Name:         "SortFilter",
-	Description:  "A JSON object used for sorting list items, includes optional field 'Field' and 'Order'.",
+	Description:  "A JSON object used for sorting list items, includes optional fields 'Field', 'Order' and 'Nulls'. A list of these objects sorts by each in turn.",
 	Serialize:    func(value interface{}) interface{} { return nil },
-	ParseValue:   func(value interface{}) interface{} { return nil },
+	ParseValue:   parseSortFilterValue,
 	ParseLiteral: func(valueAST ast.Value) interface{} { return valueAST.GetValue() },
 })
 
-type lessFunc func(i, j int) bool
+// Sortable is implemented by types which define their own order when sorting a list. Less reports whether the value
+// sorts before other, the value of the same sort field in another list item.
+type Sortable interface {
+	Less(other interface{}) bool
+}
+
+// compareFunc compares the list items at index i and j returning a negative number when i sorts before j, a positive
+// number when i sorts after j and 0 when they are equal.
+type compareFunc func(i, j int) int
 
+// sortParameters are the parameters for a single sort key. Nulls is where items with a null or missing value are
+// placed, either nullsFirst or nullsLast, the default is last for ascending order and first for descending.
 type sortParameters struct {
 	field string
 	order string
+	nulls string
+}
+
+// sortParametersJSON represents the sort parameters as defined as a JSON object.
+type sortParametersJSON struct {
+	Field string `json:"Field,omitempty"`
+	Order string `json:"Order,omitempty"`
+	Nulls string `json:"Nulls,omitempty"`
 }
 
-// parseSortParameters parses the given argument returning the sort parameters.
-// If the argument is nil the returned value is nil.
-func parseSortParameters(arg interface{}) (*sortParameters, error) {
-	if arg == nil {
+// parseSortFilterValue is the graphql.ParseValueFn for the SortFilter scalar, it is used when the sort is given as a
+// query variable rather than inline. The variable is either a single sort object or a list of them. If the variable
+// can't be decoded nil is returned which the GraphQL library reports as an invalid variable value.
+func parseSortFilterValue(value interface{}) interface{} {
+	var keys []sortParametersJSON
+	if reflect.ValueOf(value).Kind() == reflect.Slice {
+		if err := decodeVariable(value, &keys); err != nil {
+			return nil
+		}
+	} else {
+		var sj sortParametersJSON
+		if err := decodeVariable(value, &sj); err != nil {
+			return nil
+		}
+		keys = append(keys, sj)
+	}
+
+	params := make([]*sortParameters, len(keys))
+	for i, key := range keys {
+		params[i] = &sortParameters{field: key.Field, order: key.Order, nulls: key.Nulls}
+	}
+	return params
+}
+
+// parseSortParameters parses the given argument returning the sort parameters for each sort key in order.
+// The argument is either the AST object fields of an inline sort, the AST values of an inline list of sorts, the
+// sortParameters decoded from a variable or the maps of typed sort input objects.
+// If the argument is nil or an empty list the returned value is nil. Any error is a SortError.
+func parseSortParameters(arg interface{}) ([]*sortParameters, error) {
+	params, err := parseSortKeys(arg)
+	if err != nil {
+		return nil, &SortError{Code: InvalidSortCode, Err: err}
+	}
+	if len(params) == 0 {
 		return nil, nil
 	}
-	fields, ok := arg.([]*ast.ObjectField)
-	if !ok {
+	return params, nil
+}
+
+// parseSortKeys parses the sort parameters of each sort key from the argument as described in parseSortParameters.
+func parseSortKeys(arg interface{}) ([]*sortParameters, error) {
+	var params []*sortParameters
+	switch arg := arg.(type) {
+	case nil:
+		return nil, nil
+	case []*ast.ObjectField:
+		p, err := newSortParameters(arg)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, p)
+	case []ast.Value:
+		for _, value := range arg {
+			obj, ok := value.(*ast.ObjectValue)
+			if !ok {
+				return nil, errors.New("unable to parse sort argument, each item in the list must be a sort object")
+			}
+			p, err := newSortParameters(obj.Fields)
+			if err != nil {
+				return nil, err
+			}
+			params = append(params, p)
+		}
+	case map[string]interface{}:
+		p, err := newTypedSortParameters(arg)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, p)
+	case []interface{}:
+		for _, value := range arg {
+			obj, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("unable to parse sort argument, each item in the list must be a sort object")
+			}
+			p, err := newTypedSortParameters(obj)
+			if err != nil {
+				return nil, err
+			}
+			params = append(params, p)
+		}
+	case []*sortParameters:
+		for _, p := range arg {
+			if err := validateSortOrder(p.order); err != nil {
+				return nil, err
+			}
+			if err := validateSortNulls(p.nulls); err != nil {
+				return nil, err
+			}
+		}
+		params = arg
+	default:
 		return nil, errors.New("unable to parse sort argument")
 	}
+	return params, nil
+}
+
+// newTypedSortParameters parses the map of a typed sort input object.
+func newTypedSortParameters(obj map[string]interface{}) (*sortParameters, error) {
+	var params sortParameters
+	if raw, ok := obj["Field"]; ok {
+		field, ok := raw.(string)
+		if !ok {
+			return nil, errors.New("unable to parse sort argument field Field")
+		}
+		params.field = field
+	}
+	if raw, ok := obj["Order"]; ok {
+		order, ok := raw.(string)
+		if !ok {
+			return nil, errors.New("unable to parse sort argument field Order")
+		}
+		if err := validateSortOrder(order); err != nil {
+			return nil, err
+		}
+		params.order = order
+	}
+	if raw, ok := obj["Nulls"]; ok {
+		nulls, ok := raw.(string)
+		if !ok {
+			return nil, errors.New("unable to parse sort argument field Nulls")
+		}
+		if err := validateSortNulls(nulls); err != nil {
+			return nil, err
+		}
+		params.nulls = nulls
+	}
+	return &params, nil
+}
 
+// newSortParameters parses the AST ObjectFields of an inline sort argument.
+func newSortParameters(fields []*ast.ObjectField) (*sortParameters, error) {
 	var params sortParameters
 	for _, f := range fields {
 		switch f.Name.Value {
@@ -54,24 +200,59 @@ func parseSortParameters(arg interface{}) (*sortParameters, error) {
 			if !ok {
 				return nil, errors.New("unable to parse sort argument field Order")
 			}
-			switch v.Value {
-			case ascending, descending, "":
-			default:
-				return nil, fmt.Errorf("sort order must be %q or %q or undefined", ascending, descending)
+			if err := validateSortOrder(v.Value); err != nil {
+				return nil, err
 			}
 			params.order = v.Value
+		case "Nulls":
+			v, ok := f.GetValue().(*ast.StringValue)
+			if !ok {
+				return nil, errors.New("unable to parse sort argument field Nulls")
+			}
+			if err := validateSortNulls(v.Value); err != nil {
+				return nil, err
+			}
+			params.nulls = v.Value
 		}
 	}
 
 	return &params, nil
 }
 
-// listSort will sort the given list in place according to the params specified.
+func validateSortOrder(order string) error {
+	switch order {
+	case ascending, descending, "":
+		return nil
+	default:
+		return fmt.Errorf("sort order must be %q or %q or undefined", ascending, descending)
+	}
+}
+
+func validateSortNulls(nulls string) error {
+	switch nulls {
+	case nullsFirst, nullsLast, "":
+		return nil
+	default:
+		return fmt.Errorf("sort nulls must be %q or %q or undefined", nullsFirst, nullsLast)
+	}
+}
+
+// nullsSortFirst returns true if items with a null or missing value sort before other items.
+func (params *sortParameters) nullsSortFirst() bool {
+	if params.nulls == "" {
+		return params.order == descending
+	}
+	return params.nulls == nullsFirst
+}
+
+// listSort will sort the given list in place according to the params specified. Each of the params is a sort key,
+// items are ordered by the first key with each following key used only to order items equal for all previous keys.
 // The type of the specified field is essential information when sorting but can't be determined until the list to be
 // sorted is available which is why this does not follow the golang standard sort interface.
-// An error can occur if sort the fields in the list items is not consistently the same type or an unsupported type.
-func listSort(params *sortParameters, list []interface{}) error {
-	if len(list) < 2 {
+// An error can occur if sort the fields in the list items is not consistently the same type or an unsupported type,
+// any error is a SortError.
+func listSort(params []*sortParameters, list []interface{}) error {
+	if len(list) < 2 || len(params) == 0 {
 		return nil
 	}
 
@@ -83,79 +264,264 @@ func listSort(params *sortParameters, list []interface{}) error {
 }
 
 // unprotectedListSort does the work described in listSort but can panic and so defers a recover and with that always
-// returns a error to the errChan.
-func unprotectedListSort(params *sortParameters, list []interface{}, errChan chan<- error) {
+// returns a error to the errChan. A panic can only come from the Less method of a Sortable.
+func unprotectedListSort(params []*sortParameters, list []interface{}, errChan chan<- error) {
+	var current *sortParameters // the sort key in use, reported if there is a panic
 	defer func() {
 		err := recover()
-		if err != nil {
-			errChan <- fmt.Errorf("failed to sort: %v", err)
+		if err == nil {
+			return
+		}
+		se := &SortError{Code: TypeMismatchCode, Err: fmt.Errorf("failed to sort: %v", err)}
+		if current != nil {
+			se.Field, se.Order = current.field, current.order
 		}
+		errChan <- se
 	}()
 
-	var less lessFunc
-	extracFunc := func(index int) interface{} {
-		value, err := deepExtractFieldWithError(list[index], params.field)
+	compares := make([]compareFunc, len(params))
+	for k, p := range params {
+		compare, err := newCompareFunc(p, list)
 		if err != nil {
-			panic(err)
+			errChan <- err
+			return
 		}
-		return value
+		compares[k] = compare
 	}
-	if params.field == "" {
-		extracFunc = func(index int) interface{} {
-			return list[index]
+
+	// The compare functions work on the index of items in the original list so a list of indexes is sorted and the
+	// list then reordered to match.
+	order := make([]int, len(list))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		for k, compare := range compares {
+			current = params[k]
+			if c := compare(order[i], order[j]); c != 0 {
+				return c < 0
+			}
 		}
+		return false
+	})
+
+	sorted := make([]interface{}, len(list))
+	for i, index := range order {
+		sorted[i] = list[index]
 	}
+	copy(list, sorted)
 
-	field := extracFunc(0)
-	switch field.(type) {
-	case int:
-		less = func(i, j int) bool {
-			iItem := extracFunc(i).(int)
-			jItem := extracFunc(j).(int)
+	errChan <- nil
+	return
+}
 
-			return iItem < jItem
+// newCompareFunc returns the compareFunc for a single sort key. The value of the sort field is extracted from every
+// list item up front, the kind of value compared is chosen based on the first value which isn't null and an error is
+// returned if any other item has a different kind of value. Items with a null value or missing the field are placed
+// first or last as given by the sort parameters, though an error is returned if no item has the field.
+func newCompareFunc(params *sortParameters, list []interface{}) (compareFunc, error) {
+	values := make([]interface{}, len(list))
+	nulls := make([]bool, len(list))
+	kind := nilSortKind
+	var kindRaw interface{}
+	var lookupErr error
+	var found bool
+	path := compileFieldPath(params.field)
+	for i, item := range list {
+		raw := item
+		if params.field != "" {
+			var ok bool
+			var err error
+			raw, ok, err = path.lookup(item)
+			if err != nil {
+				lookupErr = err
+			}
+			found = found || ok || err == nil
 		}
-	case int64:
-		less = func(i, j int) bool {
-			iItem := extracFunc(i).(int64)
-			jItem := extracFunc(j).(int64)
 
-			return iItem < jItem
+		value, k := sortValue(raw)
+		switch {
+		case k == nilSortKind:
+			nulls[i] = true
+			continue
+		case kind == nilSortKind && k == unsortableKind:
+			return nil, &SortError{
+				Code:  TypeMismatchCode,
+				Field: params.field,
+				Order: params.order,
+				Err:   fmt.Errorf("unknown type for sort field %q", params.field),
+			}
+		case kind == nilSortKind:
+			kind, kindRaw = k, raw
+		case k != kind:
+			return nil, &SortError{
+				Code:  TypeMismatchCode,
+				Field: params.field,
+				Order: params.order,
+				Err:   fmt.Errorf("failed to sort: sort field %q has values of type %T and %T", params.field, kindRaw, raw),
+			}
 		}
-	case string:
-		less = func(i, j int) bool {
-			iItem := extracFunc(i).(string)
-			jItem := extracFunc(j).(string)
+		values[i] = value
+	}
+	if params.field != "" && !found {
+		return nil, &SortError{Code: FieldNotFoundCode, Field: params.field, Order: params.order, Err: fmt.Errorf("failed to sort: %v", lookupErr)}
+	}
 
-			return iItem < jItem
+	var compare compareFunc
+	switch kind {
+	case sortableKind:
+		compare = func(i, j int) int {
+			a, b := values[i].(Sortable), values[j].(Sortable)
+			switch {
+			case a.Less(b):
+				return -1
+			case b.Less(a):
+				return 1
+			}
+			return 0
 		}
-	case float64:
-		less = func(i, j int) bool {
-			iItem := extracFunc(i).(float64)
-			jItem := extracFunc(j).(float64)
-
-			return iItem < jItem
+	case timeSortKind:
+		compare = func(i, j int) int {
+			a, b := values[i].(time.Time), values[j].(time.Time)
+			switch {
+			case a.Before(b):
+				return -1
+			case a.After(b):
+				return 1
+			}
+			return 0
+		}
+	case boolSortKind:
+		compare = func(i, j int) int {
+			a, b := values[i].(bool), values[j].(bool)
+			switch {
+			case !a && b:
+				return -1
+			case a && !b:
+				return 1
+			}
+			return 0
+		}
+	case numberSortKind:
+		compare = func(i, j int) int {
+			c, _ := compareNumbers(values[i], values[j])
+			return c
+		}
+	case stringSortKind:
+		compare = func(i, j int) int {
+			return strings.Compare(values[i].(string), values[j].(string))
 		}
-	case nil:
-		errChan <- fmt.Errorf("unable to extract sort field %q", params.field)
-		return
-	default:
-		errChan <- fmt.Errorf("unknown type for sort field %q", params.field)
-		return
 	}
 
+	if compare == nil { // every value is null
+		return func(i, j int) int { return 0 }, nil
+	}
 	if params.order == descending {
-		less = notLess(less)
+		compare = reverseCompare(compare)
 	}
 
-	sort.SliceStable(list, less) // This can panic if the less function encounters an inconsistent type
+	nullOrder := 1
+	if params.nullsSortFirst() {
+		nullOrder = -1
+	}
+	return func(i, j int) int {
+		switch {
+		case nulls[i] && nulls[j]:
+			return 0
+		case nulls[i]:
+			return nullOrder
+		case nulls[j]:
+			return -nullOrder
+		}
+		return compare(i, j)
+	}, nil
+}
 
-	errChan <- nil
-	return
+// sortKind is the kind of value compared when sorting.
+type sortKind int
+
+const (
+	unsortableKind sortKind = iota
+	nilSortKind
+	sortableKind
+	timeSortKind
+	boolSortKind
+	numberSortKind
+	stringSortKind
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// sortValue returns the value to compare when sorting by the raw value along with its kind. A Sortable is returned as
+// is, otherwise pointers are followed and the value converted to a time.Time, bool, string or a canonical number as
+// returned by coerceValue based on its kind so named types are sorted as the type they are built on. Numbers of any
+// width are the same kind so integers and floats are sorted together.
+func sortValue(raw interface{}) (interface{}, sortKind) {
+	if isNil(raw) {
+		return nil, nilSortKind
+	}
+	if sortable, ok := raw.(Sortable); ok {
+		return sortable, sortableKind
+	}
+
+	v := reflect.ValueOf(raw)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nilSortKind
+		}
+		v = v.Elem()
+	}
+	if v.CanInterface() {
+		if sortable, ok := v.Interface().(Sortable); ok {
+			return sortable, sortableKind
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if v.Type().ConvertibleTo(timeType) {
+			return v.Convert(timeType).Interface(), timeSortKind
+		}
+	case reflect.Bool:
+		return v.Bool(), boolSortKind
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), numberSortKind
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := v.Uint()
+		if u <= math.MaxInt64 {
+			return int64(u), numberSortKind
+		}
+		return u, numberSortKind
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), numberSortKind
+	case reflect.String:
+		return v.String(), stringSortKind
+	}
+	return nil, unsortableKind
+}
+
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareFloat64(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
 }
 
-func notLess(parent lessFunc) lessFunc {
-	return func(i, j int) bool {
-		return !parent(i, j)
+func reverseCompare(parent compareFunc) compareFunc {
+	return func(i, j int) int {
+		return parent(j, i)
 	}
 }
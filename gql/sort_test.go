@@ -3,8 +3,10 @@ package gql
 import (
 	"context"
 	"encoding/json"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/GannettDigital/graphql"
 )
@@ -15,6 +17,7 @@ func TestListSort(t *testing.T) {
 	tests := []struct {
 		description string
 		query       string
+		variables   map[string]interface{}
 		want        string
 		wantErr     bool
 		wantLF      *ListFunctions
@@ -161,6 +164,160 @@ func TestListSort(t *testing.T) {
 			wantLF: &ListFunctions{
 				SortField: "name",
 				SortOrder: "ASC",
+				SortKeys:  []ListSortKey{{Field: "name", Order: "ASC"}},
+			},
+		},
+		{
+			description: "single key sort is stable",
+			query:       `query { q(id: "1"){ items(sort: {Field: "priority", Order: "DESC"}){name priority}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"c","priority":2},{"name":"b","priority":2},{"name":"a","priority":1},{"name":"d","priority":1},{"name":"e","priority":1}]}}}`,
+		},
+		{
+			description: "single key sort in a list",
+			query:       `query { q(id: "1"){ items(sort: [{Field: "name", Order: "DESC"}]){name}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"e"},{"name":"d"},{"name":"c"},{"name":"b"},{"name":"a"}]}}}`,
+		},
+		{
+			description: "multiple key sort",
+			query:       `query { q(id: "1"){ items(sort: [{Field: "priority", Order: "DESC"}, {Field: "name"}]){name priority}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"b","priority":2},{"name":"c","priority":2},{"name":"a","priority":1},{"name":"d","priority":1},{"name":"e","priority":1}]}}}`,
+		},
+		{
+			description: "multiple key sort, second key descending",
+			query:       `query { q(id: "1"){ items(sort: [{Field: "priority"}, {Field: "floatvalue", Order: "DESC"}]){name priority}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"e","priority":1},{"name":"d","priority":1},{"name":"a","priority":1},{"name":"c","priority":2},{"name":"b","priority":2}]}}}`,
+		},
+		{
+			description: "multiple key sort and limit filter",
+			query:       `query { q(id: "1"){ items(sort: [{Field: "priority", Order: "DESC"}, {Field: "name", Order: "DESC"}], filter: {Operation: "LIMIT", Argument: {Value: 3}}){name}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"c"},{"name":"b"},{"name":"e"}]}}}`,
+		},
+		{
+			description: "empty sort list",
+			query:       `query { q(id: "1"){ items(sort: []){name}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"c"},{"name":"a"},{"name":"d"},{"name":"b"},{"name":"e"}]}}}`,
+		},
+		{
+			description: "invalid sort, list item not an object",
+			query:       `query { q(id: "1"){ items(sort: [{Field: "name"}, "value"]){name}}}`,
+			wantErr:     true,
+		},
+		{
+			description: "invalid sort, second key order is invalid",
+			query:       `query { q(id: "1"){ items(sort: [{Field: "name"}, {Field: "value", Order: "foo"}]){name}}}`,
+			wantErr:     true,
+		},
+		{
+			description: "invalid sort, second key field not found",
+			query:       `query { q(id: "1"){ items(sort: [{Field: "priority"}, {Field: "notafield"}]){name}}}`,
+			wantErr:     true,
+		},
+		{
+			description: "multiple key sort from variable",
+			query:       `query($s: SortFilter) { q(id: "1"){ items(sort: $s){name priority}}}`,
+			variables: map[string]interface{}{"s": []interface{}{
+				map[string]interface{}{"Field": "priority", "Order": "DESC"},
+				map[string]interface{}{"Field": "name"},
+			}},
+			want: `{"data":{"q":{"items":[{"name":"b","priority":2},{"name":"c","priority":2},{"name":"a","priority":1},{"name":"d","priority":1},{"name":"e","priority":1}]}}}`,
+		},
+		{
+			description: "invalid multiple key sort from variable, order is invalid",
+			query:       `query($s: SortFilter) { q(id: "1"){ items(sort: $s){name}}}`,
+			variables: map[string]interface{}{"s": []interface{}{
+				map[string]interface{}{"Field": "priority"},
+				map[string]interface{}{"Field": "name", "Order": "foo"},
+			}},
+			wantErr: true,
+		},
+		{
+			description: "reporting multiple key sort",
+			query:       `query { q(id: "1"){ items(sort: [{Field: "priority", Order: "DESC"}, {Field: "name"}]){name}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"b"},{"name":"c"},{"name":"a"},{"name":"d"},{"name":"e"}]}}}`,
+			wantLF: &ListFunctions{
+				SortField: "priority",
+				SortOrder: "DESC",
+				SortKeys:  []ListSortKey{{Field: "priority", Order: "DESC"}, {Field: "name"}},
+			},
+		},
+		{
+			description: "string sort descending from variable",
+			query:       `query($s: SortFilter) { q(id: "1"){ items(sort: $s){name}}}`,
+			variables:   map[string]interface{}{"s": map[string]interface{}{"Field": "name", "Order": "DESC"}},
+			want:        `{"data":{"q":{"items":[{"name":"e"},{"name":"d"},{"name":"c"},{"name":"b"},{"name":"a"}]}}}`,
+		},
+		{
+			description: "int sort, no field, descending from variable",
+			query:       `query($s: SortFilter) { q(id: "1"){ intlist(sort: $s)}}`,
+			variables:   map[string]interface{}{"s": map[string]interface{}{"Order": "DESC"}},
+			want:        `{"data":{"q":{"intlist":[4,3,2,1]}}}`,
+		},
+		{
+			description: "string sort and limit filter from variables",
+			query:       `query($f: ListFilter, $s: SortFilter) { q(id: "1"){ items(filter: $f, sort: $s){name}}}`,
+			variables: map[string]interface{}{
+				"f": map[string]interface{}{"Operation": "LIMIT", "Argument": map[string]interface{}{"Value": float64(2)}},
+				"s": map[string]interface{}{"Field": "name"},
+			},
+			want: `{"data":{"q":{"items":[{"name":"a"},{"name":"b"}]}}}`,
+		},
+		{
+			description: "invalid sort from variable, order is invalid",
+			query:       `query($s: SortFilter) { q(id: "1"){ items(sort: $s){name}}}`,
+			variables:   map[string]interface{}{"s": map[string]interface{}{"Field": "name", "Order": "foo"}},
+			wantErr:     true,
+		},
+		{
+			description: "invalid sort from variable, order not a string",
+			query:       `query($s: SortFilter) { q(id: "1"){ items(sort: $s){name}}}`,
+			variables:   map[string]interface{}{"s": map[string]interface{}{"Field": "name", "Order": 1.2}},
+			wantErr:     true,
+		},
+		{
+			description: "nested field sort, nulls last by default",
+			query:       `query { q(id: "1"){ items(sort: {Field: "parent_name"}){name}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"b"},{"name":"a"},{"name":"c"},{"name":"d"},{"name":"e"}]}}}`,
+		},
+		{
+			description: "nested field sort descending, nulls first by default",
+			query:       `query { q(id: "1"){ items(sort: {Field: "parent_name", Order: "DESC"}){name}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"c"},{"name":"d"},{"name":"e"},{"name":"a"},{"name":"b"}]}}}`,
+		},
+		{
+			description: "reporting nested field sort with nulls first",
+			query:       `query { q(id: "1"){ items(sort: [{Field: "parent_name", Nulls: "FIRST"}, {Field: "name", Order: "DESC"}]){name}}}`,
+			want:        `{"data":{"q":{"items":[{"name":"e"},{"name":"d"},{"name":"c"},{"name":"b"},{"name":"a"}]}}}`,
+			wantLF: &ListFunctions{
+				SortField: "parent_name",
+				SortKeys:  []ListSortKey{{Field: "parent_name", Nulls: "FIRST"}, {Field: "name", Order: "DESC"}},
+			},
+		},
+		{
+			description: "nested field sort with nulls last from variable",
+			query:       `query($s: SortFilter) { q(id: "1"){ items(sort: $s){name}}}`,
+			variables:   map[string]interface{}{"s": map[string]interface{}{"Field": "parent_name", "Order": "DESC", "Nulls": "LAST"}},
+			want:        `{"data":{"q":{"items":[{"name":"a"},{"name":"b"},{"name":"c"},{"name":"d"},{"name":"e"}]}}}`,
+		},
+		{
+			description: "invalid sort, nulls is invalid",
+			query:       `query { q(id: "1"){ items(sort: {Field: "name", Nulls: "MIDDLE"}){name}}}`,
+			wantErr:     true,
+		},
+		{
+			description: "invalid sort from variable, nulls is invalid",
+			query:       `query($s: SortFilter) { q(id: "1"){ items(sort: $s){name}}}`,
+			variables:   map[string]interface{}{"s": map[string]interface{}{"Field": "name", "Nulls": "MIDDLE"}},
+			wantErr:     true,
+		},
+		{
+			description: "reporting string sort from variable",
+			query:       `query($s: SortFilter) { q(id: "1"){ items(sort: $s){name}}}`,
+			variables:   map[string]interface{}{"s": map[string]interface{}{"Field": "name", "Order": "ASC"}},
+			want:        `{"data":{"q":{"items":[{"name":"a"},{"name":"b"},{"name":"c"},{"name":"d"},{"name":"e"}]}}}`,
+			wantLF: &ListFunctions{
+				SortField: "name",
+				SortOrder: "ASC",
+				SortKeys:  []ListSortKey{{Field: "name", Order: "ASC"}},
 			},
 		},
 	}
@@ -172,9 +329,10 @@ func TestListSort(t *testing.T) {
 			ctx = context.WithValue(ctx, QueryReporterContextKey, qr)
 		}
 		params := graphql.Params{
-			Context:       ctx,
-			Schema:        s,
-			RequestString: test.query,
+			Context:        ctx,
+			Schema:         s,
+			RequestString:  test.query,
+			VariableValues: test.variables,
 		}
 
 		resp := graphql.Do(params)
@@ -189,6 +347,9 @@ func TestListSort(t *testing.T) {
 			if got, want := qr.sortOrder, test.wantLF.SortOrder; got != want {
 				t.Errorf("Test %q - got sort order %q, want %q", test.description, got, want)
 			}
+			if got, want := qr.sortKeys, test.wantLF.SortKeys; !reflect.DeepEqual(got, want) {
+				t.Errorf("Test %q - got sort keys %v, want %v", test.description, got, want)
+			}
 		}
 		switch {
 		case test.wantErr && err != nil:
@@ -210,6 +371,186 @@ func TestListSort(t *testing.T) {
 	}
 }
 
+type testVersion struct {
+	major, minor int
+}
+
+func (v testVersion) Less(other interface{}) bool {
+	o := other.(testVersion)
+	return v.major < o.major || (v.major == o.major && v.minor < o.minor)
+}
+
+func TestListSortTypes(t *testing.T) {
+	type testItem struct {
+		A interface{}
+	}
+	type named string
+	type namedTime time.Time
+
+	now := time.Now()
+	one, two := 1, 2
+
+	tests := []struct {
+		description string
+		params      *sortParameters
+		in          []interface{}
+		want        []interface{}
+	}{
+		{
+			description: "bool",
+			params:      &sortParameters{field: "a"},
+			in:          []interface{}{testItem{A: true}, testItem{A: false}, testItem{A: true}},
+			want:        []interface{}{testItem{A: false}, testItem{A: true}, testItem{A: true}},
+		},
+		{
+			description: "time descending",
+			params:      &sortParameters{field: "a", order: descending},
+			in:          []interface{}{testItem{A: now.Add(-time.Hour)}, testItem{A: now}, testItem{A: now.Add(-2 * time.Hour)}},
+			want:        []interface{}{testItem{A: now}, testItem{A: now.Add(-time.Hour)}, testItem{A: now.Add(-2 * time.Hour)}},
+		},
+		{
+			description: "time pointers and named time",
+			params:      &sortParameters{},
+			in:          []interface{}{namedTime(now), &now, namedTime(now.Add(-time.Hour))},
+			want:        []interface{}{namedTime(now.Add(-time.Hour)), namedTime(now), &now},
+		},
+		{
+			description: "float32",
+			params:      &sortParameters{},
+			in:          []interface{}{float32(2.5), float32(-1), float32(2.25)},
+			want:        []interface{}{float32(-1), float32(2.25), float32(2.5)},
+		},
+		{
+			description: "integers of different widths",
+			params:      &sortParameters{},
+			in:          []interface{}{int8(3), int16(-2), int32(1), int64(0), 5},
+			want:        []interface{}{int16(-2), int64(0), int32(1), int8(3), 5},
+		},
+		{
+			description: "unsigned",
+			params:      &sortParameters{order: descending},
+			in:          []interface{}{uint8(3), uint(10), uint64(1 << 63)},
+			want:        []interface{}{uint64(1 << 63), uint(10), uint8(3)},
+		},
+		{
+			description: "mix of integers, unsigned and floats",
+			params:      &sortParameters{field: "a"},
+			in:          []interface{}{testItem{A: 1.5}, testItem{A: uint64(1 << 63)}, testItem{A: 1}, testItem{A: float32(-0.5)}, testItem{A: int8(2)}},
+			want:        []interface{}{testItem{A: float32(-0.5)}, testItem{A: 1}, testItem{A: 1.5}, testItem{A: int8(2)}, testItem{A: uint64(1 << 63)}},
+		},
+		{
+			description: "named string",
+			params:      &sortParameters{},
+			in:          []interface{}{named("b"), "c", named("a")},
+			want:        []interface{}{named("a"), named("b"), "c"},
+		},
+		{
+			description: "int pointers",
+			params:      &sortParameters{},
+			in:          []interface{}{&two, &one},
+			want:        []interface{}{&one, &two},
+		},
+		{
+			description: "Sortable",
+			params:      &sortParameters{field: "a"},
+			in:          []interface{}{testItem{A: testVersion{1, 10}}, testItem{A: testVersion{1, 2}}, testItem{A: testVersion{0, 99}}},
+			want:        []interface{}{testItem{A: testVersion{0, 99}}, testItem{A: testVersion{1, 2}}, testItem{A: testVersion{1, 10}}},
+		},
+	}
+
+	for _, test := range tests {
+		if err := listSort([]*sortParameters{test.params}, test.in); err != nil {
+			t.Errorf("Test %q - got error: %v", test.description, err)
+			continue
+		}
+		if !reflect.DeepEqual(test.in, test.want) {
+			t.Errorf("Test %q - got %v, want %v", test.description, test.in, test.want)
+		}
+	}
+}
+
+func TestListSortNulls(t *testing.T) {
+	type child struct {
+		B int
+	}
+	type testItem struct {
+		A *int
+		C *child
+	}
+	type otherItem struct {
+		Other string
+	}
+	one, two := 1, 2
+
+	tests := []struct {
+		description string
+		params      []*sortParameters
+		in          []interface{}
+		want        []interface{}
+		wantErr     bool
+	}{
+		{
+			description: "nil pointers last ascending",
+			params:      []*sortParameters{{field: "a"}},
+			in:          []interface{}{testItem{}, testItem{A: &two}, testItem{A: &one}},
+			want:        []interface{}{testItem{A: &one}, testItem{A: &two}, testItem{}},
+		},
+		{
+			description: "nil pointers first descending",
+			params:      []*sortParameters{{field: "a", order: descending}},
+			in:          []interface{}{testItem{A: &one}, testItem{}, testItem{A: &two}},
+			want:        []interface{}{testItem{}, testItem{A: &two}, testItem{A: &one}},
+		},
+		{
+			description: "nulls first ascending",
+			params:      []*sortParameters{{field: "a", nulls: nullsFirst}},
+			in:          []interface{}{testItem{A: &two}, testItem{A: &one}, testItem{}},
+			want:        []interface{}{testItem{}, testItem{A: &one}, testItem{A: &two}},
+		},
+		{
+			description: "nulls last descending",
+			params:      []*sortParameters{{field: "a", order: descending, nulls: nullsLast}},
+			in:          []interface{}{testItem{}, testItem{A: &one}, testItem{A: &two}},
+			want:        []interface{}{testItem{A: &two}, testItem{A: &one}, testItem{}},
+		},
+		{
+			description: "missing nested field and missing field",
+			params:      []*sortParameters{{field: "c_b"}},
+			in:          []interface{}{otherItem{Other: "x"}, testItem{}, testItem{C: &child{B: 2}}, testItem{C: &child{B: 1}}},
+			want:        []interface{}{testItem{C: &child{B: 1}}, testItem{C: &child{B: 2}}, otherItem{Other: "x"}, testItem{}},
+		},
+		{
+			description: "every value null",
+			params:      []*sortParameters{{field: "a"}},
+			in:          []interface{}{testItem{C: &child{B: 1}}, testItem{}},
+			want:        []interface{}{testItem{C: &child{B: 1}}, testItem{}},
+		},
+		{
+			description: "nil list items",
+			params:      []*sortParameters{{}},
+			in:          []interface{}{"b", nil, "a"},
+			want:        []interface{}{"a", "b", nil},
+		},
+		{
+			description: "field missing from every item",
+			params:      []*sortParameters{{field: "d"}},
+			in:          []interface{}{testItem{}, testItem{}},
+			wantErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		err := listSort(test.params, test.in)
+		if gotErr := err != nil; gotErr != test.wantErr {
+			t.Errorf("Test %q - got error %v, want error %t", test.description, err, test.wantErr)
+			continue
+		}
+		if !test.wantErr && !reflect.DeepEqual(test.in, test.want) {
+			t.Errorf("Test %q - got %v, want %v", test.description, test.in, test.want)
+		}
+	}
+}
+
 func TestListSortFailures(t *testing.T) {
 	type testItem struct {
 		A interface{}
@@ -276,15 +617,6 @@ func TestListSortFailures(t *testing.T) {
 			},
 			errPrefix: "failed to sort",
 		},
-		{
-			description: "List fields is mix of int and float",
-			params:      &sortParameters{field: "a"},
-			in: []interface{}{
-				testItem{A: 0},
-				testItem{A: 1.1},
-			},
-			errPrefix: "failed to sort",
-		},
 		{
 			description: "mix of int and string, no field",
 			params:      &sortParameters{},
@@ -294,7 +626,7 @@ func TestListSortFailures(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		err := listSort(test.params, test.in)
+		err := listSort([]*sortParameters{test.params}, test.in)
 		if err == nil {
 			t.Fatalf("Test %q - want error got none", test.description)
 		}
@@ -303,3 +635,16 @@ func TestListSortFailures(t *testing.T) {
 		}
 	}
 }
+
+func BenchmarkListSort(b *testing.B) {
+	articles := benchmarkArticles(1000)
+	params := []*sortParameters{{field: "author_name"}, {field: "wordCount", order: descending}}
+	list := make([]interface{}, len(articles))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		copy(list, articles)
+		if err := listSort(params, list); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package gql
+
+import "github.com/GannettDigital/graphql"
+
+// StaticFields builds the fields of a GraphQL object or interface without reflection, the parent is the name used for
+// the fields as described for NewObjectBuilder. It is implemented by the code generated with cmd/graphql-gen for each
+// struct, see BuildStaticType.
+type StaticFields func(ob *ObjectBuilder, parent string) graphql.Fields
+
+// BuildStaticInterface creates the GraphQL interface for the embedded struct with the given name just as
+// BuildInterfaces does but with fields built by StaticFields rather than with reflection. It is used by generated
+// code along with BuildStaticType in place of BuildInterfaces and BuildTypes, the ObjectBuilder for generated code is
+// created without any structs but otherwise configured as usual.
+func (ob *ObjectBuilder) BuildStaticInterface(name string, fields StaticFields) *graphql.Interface {
+	if ob.interfaces == nil {
+		ob.interfaceFields = make(map[string]graphql.Fields)
+		ob.interfaces = make(map[string]*graphql.Interface)
+	}
+	return ob.buildInterface(name, ob.staticFields(fields))
+}
+
+// BuildStaticType creates the GraphQL type for the struct with the given name just as BuildTypes does but with fields
+// built by StaticFields rather than with reflection. Embeds are the names of the root level embedded structs, the
+// interfaces for them must already be built with BuildStaticInterface.
+func (ob *ObjectBuilder) BuildStaticType(name string, embeds []string, fields StaticFields) graphql.Type {
+	return ob.buildNamedType(name, embeds, ob.staticFields(fields))
+}
+
+// BuildStaticObject creates the GraphQL object for a struct within the field with the given name and parent, it is
+// the object used for the field or the items of the field if it is a list.
+func (ob *ObjectBuilder) BuildStaticObject(name, parent string, fields StaticFields) *graphql.Object {
+	return ob.newObject(fullFieldName(name, parent), nil, nil, ob.staticFields(fields))
+}
+
+// AddStaticListField sets up the list field with the given name in fields with the list arguments and resolve function
+// and adds the fields which accompany it, such as the total count, just as BuildTypes does for list fields. The paths
+// are those of the fields within the list items joined by FieldPathSeparator, they are used with
+// SetTypedListArguments, and aggregate is true if the struct field has the `aggregate:"true"` tag.
+// This will panic if the field is not a list.
+func (ob *ObjectBuilder) AddStaticListField(fields graphql.Fields, name, parent string, paths []string, aggregate bool) {
+	f := fields[name]
+	list, ok := graphql.GetNullable(f.Type).(*graphql.List)
+	if !ok {
+		// The function should only be used by generated code, panic so misuse is caught in unit testing
+		panic("graphQL AddStaticListField used with a non-list field")
+	}
+	fields[name] = ob.buildListField(fields, f, list, parent, paths, aggregate)
+}
+
+// ReportQueriedField reports the field with the given name and parent to the QueryReporter in the context, if there is
+// one, just as ResolveByField does. It is used by the resolve functions of generated code which access fields directly.
+func ReportQueriedField(p graphql.ResolveParams, name, parent string) error {
+	if qr, ok := p.Context.Value(QueryReporterContextKey).(QueryReporter); ok && qr != nil {
+		return qr.QueriedField(fullFieldName(name, parent))
+	}
+	return nil
+}
+
+// staticFields returns the fieldsFunc which builds fields with the StaticFields.
+func (ob *ObjectBuilder) staticFields(fields StaticFields) fieldsFunc {
+	return func(parent string, baseFields graphql.Fields) graphql.Fields {
+		gfields := graphql.Fields{}
+		for name, f := range baseFields {
+			gfields[name] = f
+		}
+		for name, f := range fields(ob, parent) {
+			gfields[name] = f
+		}
+		ob.addFieldAdditions(gfields, parent)
+		return gfields
+	}
+}
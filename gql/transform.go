@@ -0,0 +1,223 @@
+package gql
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+)
+
+// ListTransforms is the set of list transform operations available for use by ResolveListField.
+// The string is the operation, ie 'LIMIT' which matches a particular NewListTransform function.
+// The default implementations are pre-populated and additional transforms can be added before running the
+// ObjectBuilder.
+var ListTransforms = map[string]NewListTransform{
+	"LIMIT":    NewLimitTransform,
+	"OFFSET":   NewOffsetTransform,
+	"DISTINCT": NewDistinctTransform,
+	"REVERSE":  NewReverseTransform,
+	"SAMPLE":   NewSampleTransform,
+}
+
+// A ListTransform is a stage in the processing of a list which works on the entire list rather than matching each
+// item as a Comparator does. Transform is given the list after sorting and filtering and returns the new list, it
+// may modify the given list.
+type ListTransform interface {
+	Transform([]interface{}) ([]interface{}, error)
+}
+
+// NewListTransform is a function which returns a ListTransform given a set of list filter arguments. The Field of the
+// filter, if any, is included in the arguments with the key "Field".
+type NewListTransform func(argument map[string]interface{}) (ListTransform, error)
+
+// NewLimitTransform returns a transform which limits the length of the list to the non-negative integer given with the
+// key "Value" in the argument.
+func NewLimitTransform(arg map[string]interface{}) (ListTransform, error) {
+	limit, err := countArgument(arg, "Value")
+	if err != nil {
+		return nil, err
+	}
+	return limitTransform{limit: limit}, nil
+}
+
+// NewOffsetTransform returns a transform which skips the number of items given as a non-negative integer with the key
+// "Value" in the argument.
+func NewOffsetTransform(arg map[string]interface{}) (ListTransform, error) {
+	offset, err := countArgument(arg, "Value")
+	if err != nil {
+		return nil, err
+	}
+	return offsetTransform{offset: offset}, nil
+}
+
+// NewDistinctTransform returns a transform which removes all but the first of any equal items in the list.
+// Optionally a field name, which may include FieldPathSeparator, can be given with the key "Field" in the argument in
+// which case items are equal when the value of that field is equal. Strings, numbers and booleans are compared just as
+// NewEqualComparator does, other values must be deeply equal.
+func NewDistinctTransform(arg map[string]interface{}) (ListTransform, error) {
+	var field string
+	if raw, ok := arg["Field"]; ok {
+		field, ok = raw.(string)
+		if !ok {
+			return nil, errors.New("filter argument 'Field' must be a string")
+		}
+	}
+	return distinctTransform{field: field}, nil
+}
+
+// NewReverseTransform returns a transform which reverses the order of the list, no argument is needed.
+func NewReverseTransform(arg map[string]interface{}) (ListTransform, error) {
+	return reverseTransform{}, nil
+}
+
+// NewSampleTransform returns a transform which picks a random sample of the list, the sample size is given as a
+// non-negative integer with the key "Value" in the argument. The items in the sample keep their order in the list.
+// An integer with the key "Seed" can be given to make the sample repeatable.
+func NewSampleTransform(arg map[string]interface{}) (ListTransform, error) {
+	size, err := countArgument(arg, "Value")
+	if err != nil {
+		return nil, err
+	}
+	t := sampleTransform{size: size}
+	if raw, ok := arg["Seed"]; ok {
+		seed, ok := raw.(int)
+		if !ok {
+			return nil, errors.New("filter argument 'Seed' must be an integer")
+		}
+		t.seed = int64(seed)
+		t.seeded = true
+	}
+	return t, nil
+}
+
+// countArgument returns the non-negative integer in the argument with the given key.
+func countArgument(arg map[string]interface{}, key string) (int, error) {
+	raw, ok := arg[key]
+	if !ok {
+		return 0, fmt.Errorf("filter argument is missing '%s'", key)
+	}
+	count, ok := raw.(int)
+	if !ok {
+		return 0, fmt.Errorf("filter argument '%s' must be an integer", key)
+	}
+	if count < 0 {
+		return 0, fmt.Errorf("filter argument '%s' must not be negative", key)
+	}
+	return count, nil
+}
+
+type limitTransform struct {
+	limit int
+}
+
+func (t limitTransform) Transform(list []interface{}) ([]interface{}, error) {
+	if len(list) > t.limit {
+		return list[:t.limit], nil
+	}
+	return list, nil
+}
+
+type offsetTransform struct {
+	offset int
+}
+
+func (t offsetTransform) Transform(list []interface{}) ([]interface{}, error) {
+	if len(list) < t.offset {
+		return list[:0], nil
+	}
+	return list[t.offset:], nil
+}
+
+type distinctTransform struct {
+	field string
+}
+
+func (t distinctTransform) Transform(list []interface{}) ([]interface{}, error) {
+	seen := make(map[interface{}]bool)
+	var others []interface{} // values which can't be used as a map key are compared with reflect.DeepEqual
+	var distinct []interface{}
+	path := compileFieldPath(t.field)
+	for _, item := range list {
+		value := item
+		if t.field != "" {
+			var err error
+			value, _, err = path.lookup(item)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if key, ok := distinctKey(value); ok {
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		} else {
+			duplicate := false
+			for _, other := range others {
+				if reflect.DeepEqual(value, other) {
+					duplicate = true
+					break
+				}
+			}
+			if duplicate {
+				continue
+			}
+			others = append(others, value)
+		}
+		distinct = append(distinct, item)
+	}
+	return distinct, nil
+}
+
+// distinctKey returns the canonical value as returned by coerceValue to use as a map key, integral floats are
+// converted to an int64 so they have the same key as the equal integer.
+func distinctKey(raw interface{}) (interface{}, bool) {
+	if isNil(raw) {
+		return nil, true
+	}
+	value, ok := coerceValue(raw)
+	if !ok {
+		return nil, false
+	}
+	if f, ok := value.(float64); ok && f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+		return int64(f), true
+	}
+	return value, true
+}
+
+type reverseTransform struct{}
+
+func (t reverseTransform) Transform(list []interface{}) ([]interface{}, error) {
+	for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+		list[i], list[j] = list[j], list[i]
+	}
+	return list, nil
+}
+
+type sampleTransform struct {
+	size   int
+	seed   int64
+	seeded bool
+}
+
+func (t sampleTransform) Transform(list []interface{}) ([]interface{}, error) {
+	if len(list) <= t.size {
+		return list, nil
+	}
+
+	perm := rand.Perm
+	if t.seeded {
+		perm = rand.New(rand.NewSource(t.seed)).Perm
+	}
+	indexes := perm(len(list))[:t.size]
+	sort.Ints(indexes)
+
+	sample := make([]interface{}, t.size)
+	for i, index := range indexes {
+		sample[i] = list[index]
+	}
+	return sample, nil
+}
@@ -0,0 +1,188 @@
+package gql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTransforms(t *testing.T) {
+	type pair struct {
+		Id   string
+		Name string
+	}
+
+	tests := []struct {
+		description          string
+		transform            NewListTransform
+		arguments            map[string]interface{}
+		list                 []interface{}
+		want                 []interface{}
+		wantInvalidTransform bool
+		wantErr              bool
+	}{
+		{
+			description: "LIMIT shorter list",
+			transform:   NewLimitTransform,
+			arguments:   map[string]interface{}{"Value": 2},
+			list:        []interface{}{"a", "b", "c"},
+			want:        []interface{}{"a", "b"},
+		},
+		{
+			description: "LIMIT longer than list",
+			transform:   NewLimitTransform,
+			arguments:   map[string]interface{}{"Value": 5},
+			list:        []interface{}{"a", "b"},
+			want:        []interface{}{"a", "b"},
+		},
+		{
+			description: "LIMIT to 0",
+			transform:   NewLimitTransform,
+			arguments:   map[string]interface{}{"Value": 0},
+			list:        []interface{}{"a", "b"},
+			want:        []interface{}{},
+		},
+		{
+			description:          "LIMIT invalid argument, missing value",
+			transform:            NewLimitTransform,
+			arguments:            map[string]interface{}{"nothing": "a"},
+			wantInvalidTransform: true,
+		},
+		{
+			description:          "LIMIT invalid argument, string value",
+			transform:            NewLimitTransform,
+			arguments:            map[string]interface{}{"Value": "a"},
+			wantInvalidTransform: true,
+		},
+		{
+			description:          "LIMIT invalid argument, negative value",
+			transform:            NewLimitTransform,
+			arguments:            map[string]interface{}{"Value": -1},
+			wantInvalidTransform: true,
+		},
+		{
+			description: "OFFSET",
+			transform:   NewOffsetTransform,
+			arguments:   map[string]interface{}{"Value": 1},
+			list:        []interface{}{"a", "b", "c"},
+			want:        []interface{}{"b", "c"},
+		},
+		{
+			description: "OFFSET past the end of the list",
+			transform:   NewOffsetTransform,
+			arguments:   map[string]interface{}{"Value": 4},
+			list:        []interface{}{"a", "b", "c"},
+			want:        []interface{}{},
+		},
+		{
+			description:          "OFFSET invalid argument, float value",
+			transform:            NewOffsetTransform,
+			arguments:            map[string]interface{}{"Value": 1.5},
+			wantInvalidTransform: true,
+		},
+		{
+			description: "DISTINCT items",
+			transform:   NewDistinctTransform,
+			list:        []interface{}{"a", "b", "a", 1, 1.0, int64(1), nil, nil},
+			want:        []interface{}{"a", "b", 1, nil},
+		},
+		{
+			description: "DISTINCT struct items",
+			transform:   NewDistinctTransform,
+			list:        []interface{}{TestBase{Id: "a"}, TestBase{Id: "b"}, TestBase{Id: "a"}},
+			want:        []interface{}{TestBase{Id: "a"}, TestBase{Id: "b"}},
+		},
+		{
+			description: "DISTINCT by field",
+			transform:   NewDistinctTransform,
+			arguments:   map[string]interface{}{"Field": "id"},
+			list:        []interface{}{pair{Id: "a", Name: "1"}, pair{Id: "b", Name: "2"}, pair{Id: "a", Name: "3"}},
+			want:        []interface{}{pair{Id: "a", Name: "1"}, pair{Id: "b", Name: "2"}},
+		},
+		{
+			description: "DISTINCT by field, field not found",
+			transform:   NewDistinctTransform,
+			arguments:   map[string]interface{}{"Field": "bogus"},
+			list:        []interface{}{TestBase{Id: "a"}},
+			wantErr:     true,
+		},
+		{
+			description:          "DISTINCT invalid argument, int field",
+			transform:            NewDistinctTransform,
+			arguments:            map[string]interface{}{"Field": 1},
+			wantInvalidTransform: true,
+		},
+		{
+			description: "REVERSE",
+			transform:   NewReverseTransform,
+			list:        []interface{}{"a", "b", "c"},
+			want:        []interface{}{"c", "b", "a"},
+		},
+		{
+			description: "SAMPLE larger than list",
+			transform:   NewSampleTransform,
+			arguments:   map[string]interface{}{"Value": 3},
+			list:        []interface{}{"a", "b"},
+			want:        []interface{}{"a", "b"},
+		},
+		{
+			description: "SAMPLE with seed",
+			transform:   NewSampleTransform,
+			arguments:   map[string]interface{}{"Value": 2, "Seed": 1},
+			list:        []interface{}{"a", "b", "c", "d"},
+			want:        []interface{}{"a", "b"},
+		},
+		{
+			description:          "SAMPLE invalid argument, string seed",
+			transform:            NewSampleTransform,
+			arguments:            map[string]interface{}{"Value": 2, "Seed": "a"},
+			wantInvalidTransform: true,
+		},
+	}
+
+	for _, test := range tests {
+		transform, err := test.transform(test.arguments)
+		switch {
+		case err != nil && test.wantInvalidTransform:
+			continue
+		case err == nil && test.wantInvalidTransform:
+			t.Errorf("Test %q - got nil error want error on invalid transform", test.description)
+			continue
+		case err != nil && !test.wantInvalidTransform:
+			t.Errorf("Test %q - got invalid transform error want nil: %v", test.description, err)
+			continue
+		}
+
+		got, err := transform.Transform(test.list)
+		if (err != nil) != test.wantErr {
+			t.Errorf("Test %q - got err %v, want err %t", test.description, err, test.wantErr)
+		}
+		if test.wantErr {
+			continue
+		}
+		if len(got) != 0 || len(test.want) != 0 {
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Test %q - got %v, want %v", test.description, got, test.want)
+			}
+		}
+	}
+}
+
+func TestSampleTransformSize(t *testing.T) {
+	transform, err := NewSampleTransform(map[string]interface{}{"Value": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := []interface{}{1, 2, 3, 4, 5, 6}
+	got, err := transform.Transform(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("got sample of length %d, want 3", len(got))
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i].(int) <= got[i-1].(int) {
+			t.Errorf("got sample %v, want items in list order", got)
+		}
+	}
+}
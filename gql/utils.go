@@ -1,9 +1,12 @@
 package gql
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"strings"
+	"sync"
 
 	"github.com/GannettDigital/graphql"
 )
@@ -18,68 +21,284 @@ func DeepExtractField(s interface{}, key string) interface{} {
 // The key is expected to use FieldPathSeparator to distinguish the multiple levels.
 // This will throw an error if the value for the first split cannot be found.
 func deepExtractFieldWithError(s interface{}, key string) (interface{}, error) {
-	splits := strings.Split(key, FieldPathSeparator)
+	value, _, err := lookupFieldPath(s, key)
+	return value, err
+}
+
+// lookupFieldPath retrieves the value of a field in a multilevel object just as deepExtractFieldWithError but also
+// returns whether every level of the path was found. Pointers are followed at every level, a path is not found if
+// any level is missing from its parent or a parent is nil, in which case the value is nil.
+// An error is returned only if the first level cannot be found, a first level field which exists with a nil value is
+// found and is not an error.
+func lookupFieldPath(s interface{}, key string) (interface{}, bool, error) {
+	return compileFieldPath(key).lookup(s)
+}
+
+// fieldPath is a field key split on FieldPathSeparator, compiling a path once avoids splitting the key for every
+// item in a list.
+type fieldPath []string
+
+// compileFieldPath returns the fieldPath for a key which may include FieldPathSeparator.
+func compileFieldPath(key string) fieldPath {
+	return strings.Split(key, FieldPathSeparator)
+}
 
+// lookup retrieves the value of the field at the path within s as described for lookupFieldPath.
+func (path fieldPath) lookup(s interface{}) (interface{}, bool, error) {
 	value := s
-	for i, split := range splits {
-		value = ExtractField(value, split)
-		if value == nil {
+	for i, split := range path {
+		if i > 0 && isNil(value) {
+			return nil, false, nil
+		}
+		var found bool
+		value, found = lookupField(jsonValue(value), split)
+		if !found {
 			if i == 0 {
-				return nil, fmt.Errorf("unable to find field to extract: %q", split)
+				return nil, false, fmt.Errorf("unable to find field to extract: %q", split)
 			}
-			return nil, nil
+			return nil, false, nil
 		}
 	}
 
-	return value, nil
+	return value, true, nil
+}
+
+// FieldResolver is implemented by sources which resolve their own fields rather than having them found by reflection,
+// for instance a type with generated accessors or one which loads fields on demand. ResolveGraphQLField returns the
+// value of the field with the given name, as returned by fieldName for the equivalent struct field, and whether the
+// source has that field. It is used by ExtractField and so by the default resolvers, filters and sorts.
+type FieldResolver interface {
+	ResolveGraphQLField(name string) (interface{}, bool)
 }
 
 // ExtractField returns the value of a field from a struct, the key is the field name, which is matched
 // to the output from the fieldName function. This function also handles searching any root level embedded structs.
-// If the key does not match a field in the struct or the provided interface is not a struct nil is returned.
+// A pointer to a struct is dereferenced as are embedded struct pointers, a field within a nil embedded struct pointer
+// is nil. The first struct with a matching field is used even if its value is nil, the struct itself is searched first
+// and then the embedded structs in order.
+//
+// The source can also be a decoded JSON document, either a map with string keys such as a map[string]interface{} or
+// a json.RawMessage. A json.RawMessage source is decoded on every call to ExtractField, the resolvers decode their
+// source once before looking up a field and ResolveByField decodes a json.RawMessage field value or list item once for
+// the resolvers of its child fields. The key is matched to a map key exactly or, failing that, ignoring case and
+// underscores so the keys of a document with the JSON encoding of a struct match the names from fieldName.
+// A json.RawMessage value in a map is decoded before it is returned.
+//
+// A source implementing FieldResolver is asked for the field instead of using reflection.
+//
+// If the key does not match a field or the provided interface is not a struct or map nil is returned.
 func ExtractField(s interface{}, key string) interface{} {
-	sType := reflect.TypeOf(s)
-	if sType.Kind() != reflect.Struct {
+	value, _ := lookupField(jsonValue(s), key)
+	return value
+}
+
+// lookupField returns the value of a field from a struct just as ExtractField but also returns whether the key
+// matched a field so a field with a nil value can be distinguished from one which doesn't exist. A json.RawMessage
+// source must already be decoded, see jsonValue, so it isn't decoded again for every field looked up.
+// The fields of each struct type are found once and cached, see structFieldsOf.
+func lookupField(s interface{}, key string) (interface{}, bool) {
+	switch source := s.(type) {
+	case FieldResolver:
+		if isNil(source) {
+			return nil, false
+		}
+		return source.ResolveGraphQLField(key)
+	case map[string]interface{}:
+		if value, ok := source[key]; ok {
+			return jsonValue(value), true
+		}
+	}
+
+	sValue, ok := structValue(reflect.ValueOf(s))
+	if !ok {
+		if sValue.Kind() == reflect.Map && sValue.Type().Key().Kind() == reflect.String {
+			return lookupMapField(sValue, key)
+		}
+		return nil, false
+	}
+
+	index, ok := structFieldsOf(sValue.Type())[key]
+	if !ok {
+		return nil, false
+	}
+	for _, i := range index[:len(index)-1] {
+		embed := sValue.Field(i)
+		if embed.Kind() == reflect.Ptr {
+			if embed.IsNil() {
+				return nil, true
+			}
+			embed = embed.Elem()
+		}
+		sValue = embed
+	}
+	fieldValue := sValue.Field(index[len(index)-1])
+	if !fieldValue.CanInterface() {
+		return nil, false
+	}
+	return fieldValue.Interface(), true
+}
+
+// lookupMapField returns the value of the map key matching the field key as described for ExtractField. If several
+// keys match ignoring case and underscores the first in sorted order is used so the result is consistent.
+func lookupMapField(m reflect.Value, key string) (interface{}, bool) {
+	keyType := m.Type().Key()
+	if value := m.MapIndex(reflect.ValueOf(key).Convert(keyType)); value.IsValid() {
+		return jsonValue(value.Interface()), true
+	}
+
+	var match reflect.Value
+	iter := m.MapRange()
+	for iter.Next() {
+		mapKey := iter.Key().String()
+		if !strings.EqualFold(strings.Replace(mapKey, "_", "", -1), key) {
+			continue
+		}
+		if !match.IsValid() || mapKey < match.String() {
+			match = iter.Key()
+		}
+	}
+	if !match.IsValid() {
+		return nil, false
+	}
+	return jsonValue(m.MapIndex(match).Interface()), true
+}
+
+// jsonValue returns the value decoded if it is a json.RawMessage, otherwise it is returned as is. Numbers are decoded
+// as float64 just as when decoding a whole document into an interface{}.
+func jsonValue(value interface{}) interface{} {
+	raw, ok := value.(json.RawMessage)
+	if !ok {
+		return value
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
 		return nil
 	}
+	return decoded
+}
+
+// decodeJSON returns the value decoded if it is a json.RawMessage or, for a list, with each json.RawMessage item
+// decoded. Resolvers decode document values once this way so the fields of the value aren't decoded again on every
+// lookup by child resolvers and comparators. A list without json.RawMessage items is returned as is.
+func decodeJSON(value interface{}) interface{} {
+	if _, ok := value.(json.RawMessage); ok {
+		return jsonValue(value)
+	}
+	list := reflect.ValueOf(value)
+	if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+		return value
+	}
+	var items []interface{}
+	for i := 0; i < list.Len(); i++ {
+		item := list.Index(i).Interface()
+		if _, ok := item.(json.RawMessage); ok && items == nil {
+			items = make([]interface{}, list.Len())
+			for j := 0; j < i; j++ {
+				items[j] = list.Index(j).Interface()
+			}
+		}
+		if items != nil {
+			items[i] = jsonValue(item)
+		}
+	}
+	if items == nil {
+		return value
+	}
+	return items
+}
 
-	sValue := reflect.ValueOf(s)
-	var embeddedFields []int
+// isDocument returns true if the source is a decoded JSON document, a map with string keys or a json.RawMessage, for
+// which a missing field is equivalent to a null one.
+func isDocument(s interface{}) bool {
+	if _, ok := s.(json.RawMessage); ok {
+		return true
+	}
+	value := reflect.ValueOf(s)
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	return value.Kind() == reflect.Map && value.Type().Key().Kind() == reflect.String
+}
+
+// structValue dereferences any pointers in the value and returns the struct it points to, false is returned if the
+// value is not a struct or a pointer to one or the pointer is nil. When false is returned for a non nil value the
+// dereferenced value is still returned.
+func structValue(value reflect.Value) (reflect.Value, bool) {
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return reflect.Value{}, false
+		}
+		value = value.Elem()
+	}
+	return value, value.Kind() == reflect.Struct
+}
+
+// structType returns the struct type of the given type after dereferencing any pointers, false is returned if it is
+// not a struct.
+func structType(sType reflect.Type) (reflect.Type, bool) {
+	for sType.Kind() == reflect.Ptr {
+		sType = sType.Elem()
+	}
+	return sType, sType.Kind() == reflect.Struct
+}
+
+// structFieldCache maps a struct reflect.Type to the structFields for that type, it is safe for concurrent use.
+var structFieldCache sync.Map
+
+// structFields maps the name of each field in a struct, as returned by fieldName, to the index sequence used to
+// retrieve it, each index but the last is that of an embedded struct or struct pointer.
+type structFields map[string][]int
+
+// structFieldsOf returns the structFields for the struct type from the cache, building them on first use.
+func structFieldsOf(sType reflect.Type) structFields {
+	if fields, ok := structFieldCache.Load(sType); ok {
+		return fields.(structFields)
+	}
+	fields := make(structFields)
+	addStructFields(fields, sType, nil, make(map[reflect.Type]bool))
+	actual, _ := structFieldCache.LoadOrStore(sType, fields)
+	return actual.(structFields)
+}
 
+// addStructFields adds the fields of the struct type to fields with the index sequence prefixed by index. A name
+// already in fields is not replaced so fields of the struct itself take precedence over those of embedded structs and
+// embedded structs are searched in order, each including any structs embedded within it. Visiting contains the types
+// currently being added so a struct which embeds a pointer to itself ends.
+func addStructFields(fields structFields, sType reflect.Type, index []int, visiting map[reflect.Type]bool) {
+	if visiting[sType] {
+		return
+	}
+	visiting[sType] = true
+	defer delete(visiting, sType)
+
+	var embedded []int
 	for i := 0; i < sType.NumField(); i++ {
 		field := sType.Field(i)
-		if field.Anonymous {
-			embeddedFields = append(embeddedFields, i)
+		if _, ok := structType(field.Type); ok && field.Anonymous {
+			embedded = append(embedded, i)
 		}
 		name := fieldName(field)
-		if name == key {
-			fieldValue := sValue.Field(i)
-			return fieldValue.Interface()
-		}
-	}
-
-	// try pulling from any embedded structs if the field was not found
-	for _, i := range embeddedFields {
-		embed := sValue.Field(i)
-		if !embed.IsValid() {
+		if _, ok := fields[name]; ok || name == "" {
 			continue
 		}
-		if result := ExtractField(embed.Interface(), key); result != nil {
-			return result
-		}
+		fields[name] = append(append([]int{}, index...), i)
 	}
 
-	return nil
+	for _, i := range embedded {
+		embedType, _ := structType(sType.Field(i).Type)
+		addStructFields(fields, embedType, append(append([]int{}, index...), i), visiting)
+	}
 }
 
 // extractEmbeds will parse a struct looking for embedded struct and it will return a mapping of the names to the
-// interface{} of any that are found
+// interface{} of any that are found. Pointers to structs are dereferenced, for the parent and for embedded structs, a
+// nil embedded struct pointer is returned as the zero value of the struct.
 func extractEmbeds(parent interface{}) map[string]interface{} {
-	sType := reflect.TypeOf(parent)
-	if sType.Kind() != reflect.Struct {
+	sValue, ok := structValue(reflect.ValueOf(parent))
+	if !ok {
 		return nil
 	}
-	sValue := reflect.ValueOf(parent)
+	sType := sValue.Type()
 
 	embeds := make(map[string]interface{})
 	for i := 0; i < sType.NumField(); i++ {
@@ -88,11 +307,15 @@ func extractEmbeds(parent interface{}) map[string]interface{} {
 			if field.PkgPath != "" { // This is empty for exported fields but not for unexported
 				continue
 			}
-			embed := sValue.Field(i)
-			if !embed.IsValid() {
+			embedType, ok := structType(field.Type)
+			if !ok {
 				continue
 			}
-			embeds[embed.Type().Name()] = embed.Interface()
+			embed, ok := structValue(sValue.Field(i))
+			if !ok {
+				embed = reflect.Zero(embedType)
+			}
+			embeds[embedType.Name()] = embed.Interface()
 		}
 	}
 
@@ -136,3 +359,44 @@ func nameIsValidGraphQL(name string) bool {
 func fullFieldName(name, parent string) string {
 	return strings.Join([]string{parent, name}, FieldPathSeparator)
 }
+
+// decodeVariable decodes a GraphQL variable value into the target. Variables arrive already decoded from the request
+// JSON as generic maps and slices so they are re-encoded and then decoded into the target, numbers are decoded as
+// json.Number so that normalizeJSONValue can distinguish integers from floats.
+func decodeVariable(value interface{}, target interface{}) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	return decoder.Decode(target)
+}
+
+// normalizeJSONValue recursively walks a value decoded by decodeVariable converting any json.Number to either an int
+// or a float64 so the result matches the values parseASTValue returns for the same inline GraphQL literal.
+func normalizeJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return int(i)
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return v.String()
+		}
+		return f
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeJSONValue(item)
+		}
+		return v
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeJSONValue(item)
+		}
+		return v
+	default:
+		return value
+	}
+}
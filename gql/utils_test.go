@@ -1,6 +1,8 @@
 package gql
 
 import (
+	"encoding/json"
+	"fmt"
 	"reflect"
 	"testing"
 )
@@ -64,6 +66,24 @@ func TestDeepExtractField(t *testing.T) {
 			key:         "ground_base_less",
 			want:        nil,
 		},
+		{
+			description: "Single level, nil interface value",
+			st:          struct{ Any interface{} }{},
+			key:         "any",
+			want:        nil,
+		},
+		{
+			description: "Two levels, pointer to struct",
+			st:          &struct1{Id: "wrong", Base: TestBase{Id: "id"}},
+			key:         "base_id",
+			want:        "id",
+		},
+		{
+			description: "Single level, nil pointer",
+			st:          (*struct1)(nil),
+			key:         "id",
+			wantErr:     true,
+		},
 	}
 
 	for _, test := range tests {
@@ -81,7 +101,131 @@ func TestDeepExtractField(t *testing.T) {
 	}
 }
 
+func TestLookupFieldPath(t *testing.T) {
+	type inner struct {
+		Name string
+		Any  interface{}
+	}
+	type outer struct {
+		Inner    inner
+		InnerPtr *inner
+	}
+
+	tests := []struct {
+		description string
+		st          interface{}
+		key         string
+		want        interface{}
+		wantFound   bool
+		wantErr     bool
+	}{
+		{
+			description: "Two levels",
+			st:          outer{Inner: inner{Name: "a"}},
+			key:         "inner_name",
+			want:        "a",
+			wantFound:   true,
+		},
+		{
+			description: "Two levels, nil value",
+			st:          outer{},
+			key:         "inner_any",
+			want:        nil,
+			wantFound:   true,
+		},
+		{
+			description: "Two levels, leaf not found",
+			st:          outer{},
+			key:         "inner_bogus",
+			wantFound:   false,
+		},
+		{
+			description: "Two levels, pointer parent",
+			st:          outer{InnerPtr: &inner{Name: "b"}},
+			key:         "innerptr_name",
+			want:        "b",
+			wantFound:   true,
+		},
+		{
+			description: "Two levels, nil parent",
+			st:          outer{},
+			key:         "innerptr_name",
+			wantFound:   false,
+		},
+		{
+			description: "Single level, not found",
+			st:          outer{},
+			key:         "bogus",
+			wantFound:   false,
+			wantErr:     true,
+		},
+		{
+			description: "Two levels, map within a struct",
+			st:          outer{Inner: inner{Any: map[string]interface{}{"name": "c"}}},
+			key:         "inner_any_name",
+			want:        "c",
+			wantFound:   true,
+		},
+		{
+			description: "Two levels, raw JSON",
+			st:          json.RawMessage(`{"inner": {"name": "d", "any": null}}`),
+			key:         "inner_any",
+			want:        nil,
+			wantFound:   true,
+		},
+		{
+			description: "Two levels, raw JSON leaf not found",
+			st:          json.RawMessage(`{"inner": {"name": "d"}}`),
+			key:         "inner_any",
+			wantFound:   false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			got, found, err := lookupFieldPath(test.st, test.key)
+
+			if (err != nil) != test.wantErr {
+				t.Errorf("Got err %v, want err %v", err, test.wantErr)
+			}
+			if found != test.wantFound {
+				t.Errorf("Got found %t, want %t", found, test.wantFound)
+			}
+			if got != test.want {
+				t.Errorf("Got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// cycleEmbedA and cycleEmbedB embed pointers to each other.
+type cycleEmbedA struct {
+	*cycleEmbedB
+	Name string
+}
+
+type cycleEmbedB struct {
+	*cycleEmbedA
+	ID string
+}
+
 func TestExtractField(t *testing.T) {
+	type unexportedBase struct {
+		Name string `json:"name"`
+	}
+	type unexportedEmbed struct {
+		unexportedBase
+		Id string `json:"id"`
+	}
+	type pointerEmbed struct {
+		*TestBase
+		*unexportedBase
+	}
+	type selfEmbed struct {
+		*selfEmbed
+		Name string
+	}
+
 	tests := []struct {
 		description string
 		st          interface{}
@@ -130,6 +274,120 @@ func TestExtractField(t *testing.T) {
 			key:         "assets",
 			want:        nil,
 		},
+		{
+			description: "field in unexported embedded base",
+			st:          unexportedEmbed{unexportedBase: unexportedBase{Name: "name"}, Id: "id"},
+			key:         "name",
+			want:        "name",
+		},
+		{
+			description: "unexported embedded base",
+			st:          unexportedEmbed{},
+			key:         "unexportedBase",
+			want:        nil,
+		},
+		{
+			description: "pointer to struct",
+			st:          &testEmbed{TestBase: TestBase{Id: "id"}, Extra: "extra"},
+			key:         "extra",
+			want:        "extra",
+		},
+		{
+			description: "field in embedded pointer base",
+			st:          &pointerEmbed{TestBase: &TestBase{Id: "id"}, unexportedBase: &unexportedBase{Name: "name"}},
+			key:         "id",
+			want:        "id",
+		},
+		{
+			description: "field in unexported embedded pointer base",
+			st:          pointerEmbed{TestBase: &TestBase{Id: "id"}, unexportedBase: &unexportedBase{Name: "name"}},
+			key:         "name",
+			want:        "name",
+		},
+		{
+			description: "field in nil embedded pointer base",
+			st:          pointerEmbed{},
+			key:         "id",
+			want:        nil,
+		},
+		{
+			description: "struct embedding a pointer to itself",
+			st:          selfEmbed{selfEmbed: &selfEmbed{Name: "inner"}, Name: "name"},
+			key:         "name",
+			want:        "name",
+		},
+		{
+			description: "structs embedding pointers to each other",
+			st:          cycleEmbedA{cycleEmbedB: &cycleEmbedB{ID: "id"}},
+			key:         "id",
+			want:        "id",
+		},
+		{
+			description: "nil pointer",
+			st:          (*TestBase)(nil),
+			key:         "id",
+			want:        nil,
+		},
+		{
+			description: "field resolver",
+			st:          testFieldResolver{fields: map[string]interface{}{"id": "resolved"}},
+			key:         "id",
+			want:        "resolved",
+		},
+		{
+			description: "field resolver, not found",
+			st:          testFieldResolver{fields: map[string]interface{}{"id": "resolved"}},
+			key:         "name",
+			want:        nil,
+		},
+		{
+			description: "field resolver, nil pointer",
+			st:          (*testFieldResolver)(nil),
+			key:         "id",
+			want:        nil,
+		},
+		{
+			description: "map key",
+			st:          map[string]interface{}{"id": "id", "Id": "other"},
+			key:         "id",
+			want:        "id",
+		},
+		{
+			description: "map key with different case and underscores",
+			st:          map[string]interface{}{"Created_At": "now", "created_at": "then"},
+			key:         "createdat",
+			want:        "now",
+		},
+		{
+			description: "map key not found",
+			st:          map[string]interface{}{"id": "id"},
+			key:         "name",
+			want:        nil,
+		},
+		{
+			description: "map of strings",
+			st:          map[string]string{"Name": "name"},
+			key:         "name",
+			want:        "name",
+		},
+		{
+			description: "map of raw JSON",
+			st:          map[string]json.RawMessage{"count": json.RawMessage(`2`)},
+			key:         "count",
+			want:        float64(2),
+		},
+		{
+			description: "raw JSON",
+			st:          json.RawMessage(`{"id": "id", "count": 1}`),
+			key:         "id",
+			want:        "id",
+		},
+		{
+			description: "raw JSON, not an object",
+			st:          json.RawMessage(`[1, 2]`),
+			key:         "id",
+			want:        nil,
+		},
 	}
 
 	for _, test := range tests {
@@ -174,6 +432,19 @@ func TestExtractEmbeds(t *testing.T) {
 			in:          twoEmbeds,
 			want:        map[string]interface{}{"TestBase": tb, "TestBase2": tb2},
 		},
+		{
+			description: "Pointer to a struct",
+			in:          &oneEmbed,
+			want:        map[string]interface{}{"TestBase": tb},
+		},
+		{
+			description: "Embedded pointers",
+			in: struct {
+				*TestBase
+				*TestBase2
+			}{TestBase: &TestBase{Id: "id"}},
+			want: map[string]interface{}{"TestBase": TestBase{Id: "id"}, "TestBase2": tb2},
+		},
 	}
 
 	for _, test := range tests {
@@ -317,3 +588,104 @@ func TestFieldName(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeJSON(t *testing.T) {
+	items := []interface{}{"a", 1}
+	tests := []struct {
+		description string
+		value       interface{}
+		want        interface{}
+	}{
+		{
+			description: "Raw JSON object",
+			value:       json.RawMessage(`{"name": "a"}`),
+			want:        map[string]interface{}{"name": "a"},
+		},
+		{
+			description: "Raw JSON items",
+			value:       []json.RawMessage{json.RawMessage(`{"name": "a"}`), json.RawMessage(`null`)},
+			want:        []interface{}{map[string]interface{}{"name": "a"}, nil},
+		},
+		{
+			description: "Raw JSON item after other items",
+			value:       []interface{}{"a", json.RawMessage(`2`)},
+			want:        []interface{}{"a", float64(2)},
+		},
+		{
+			description: "List without raw JSON",
+			value:       items,
+			want:        items,
+		},
+		{
+			description: "Other value",
+			value:       "a",
+			want:        "a",
+		},
+	}
+
+	for _, test := range tests {
+		if got := decodeJSON(test.value); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Test %q - got %#v, want %#v", test.description, got, test.want)
+		}
+	}
+}
+
+type benchmarkBase struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	CreatedAt string `json:"createdAt"`
+}
+
+type benchmarkAuthor struct {
+	Name  string `json:"name"`
+	Title string `json:"title,omitempty"`
+}
+
+type benchmarkArticle struct {
+	benchmarkBase
+
+	Headline    string           `json:"headline"`
+	Summary     string           `json:"summary,omitempty"`
+	Body        string           `json:"body"`
+	Section     string           `json:"section"`
+	Subsection  string           `json:"subsection,omitempty"`
+	URL         string           `json:"url"`
+	WordCount   int              `json:"wordCount"`
+	Position    int              `json:"position"`
+	Score       float64          `json:"score"`
+	Tags        []string         `json:"tags,omitempty"`
+	Author      benchmarkAuthor  `json:"author"`
+	Contributor *benchmarkAuthor `json:"contributor,omitempty"`
+}
+
+// benchmarkArticles returns a list of n articles with varying field values.
+func benchmarkArticles(n int) []interface{} {
+	list := make([]interface{}, n)
+	for i := range list {
+		list[i] = benchmarkArticle{
+			benchmarkBase: benchmarkBase{ID: fmt.Sprintf("id-%d", i), Type: "article"},
+			Headline:      fmt.Sprintf("headline %d", (i*7919)%n),
+			WordCount:     (i * 104729) % 5000,
+			Position:      n - i,
+			Author:        benchmarkAuthor{Name: fmt.Sprintf("author %d", i%13)},
+		}
+	}
+	return list
+}
+
+func BenchmarkExtractField(b *testing.B) {
+	article := benchmarkArticles(1)[0]
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ExtractField(article, "position")
+		ExtractField(article, "id")
+	}
+}
+
+func BenchmarkDeepExtractField(b *testing.B) {
+	article := benchmarkArticles(1)[0]
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		DeepExtractField(article, "author_name")
+	}
+}